@@ -0,0 +1,192 @@
+package darksky
+
+import (
+	"strconv"
+	"strings"
+	"time"
+)
+
+// An AlertFilter returns whether an alert should be kept.
+type AlertFilter func(*Alert) bool
+
+// An AlertDiff is the difference between two sets of alerts. Added and Updated
+// contain the alerts from the new set, Removed contains the alerts from the old
+// set.
+type AlertDiff struct {
+	Added   []*Alert
+	Updated []*Alert
+	Removed []*Alert
+}
+
+// severityRanks are the ranks of severities, from least to most severe.
+var severityRanks = map[Severity]int{
+	SeverityAdvisory: 1,
+	SeverityWatch:    2,
+	SeverityWarning:  3,
+}
+
+// AlertActiveAt returns an AlertFilter that keeps alerts that have not
+// expired at t.
+func AlertActiveAt(t time.Time) AlertFilter {
+	return func(a *Alert) bool {
+		return !a.ExpiredAt(t)
+	}
+}
+
+// AlertInRegion returns an AlertFilter that keeps alerts that apply to region.
+func AlertInRegion(region string) AlertFilter {
+	return func(a *Alert) bool {
+		return a.InRegion(region)
+	}
+}
+
+// AlertSeverityAtLeast returns an AlertFilter that keeps alerts with severity
+// s or worse.
+func AlertSeverityAtLeast(s Severity) AlertFilter {
+	return func(a *Alert) bool {
+		return a.Severity.AtLeast(s)
+	}
+}
+
+// DedupeAlerts returns alerts without duplicate keys. The first alert with each
+// key is kept.
+func DedupeAlerts(alerts []*Alert) []*Alert {
+	var result []*Alert
+	seen := make(map[string]bool)
+	for _, a := range alerts {
+		if a == nil {
+			continue
+		}
+		if key := a.Key(); !seen[key] {
+			seen[key] = true
+			result = append(result, a)
+		}
+	}
+	return result
+}
+
+// DiffAlerts returns the difference between oldAlerts and newAlerts. Alerts are
+// matched by key. An alert is updated if it has the same key as an alert in
+// oldAlerts but its other fields differ.
+func DiffAlerts(oldAlerts, newAlerts []*Alert) *AlertDiff {
+	oldAlerts = DedupeAlerts(oldAlerts)
+	oldByKey := make(map[string]*Alert)
+	for _, a := range oldAlerts {
+		oldByKey[a.Key()] = a
+	}
+	diff := &AlertDiff{}
+	newKeys := make(map[string]bool)
+	for _, a := range DedupeAlerts(newAlerts) {
+		key := a.Key()
+		newKeys[key] = true
+		switch oldAlert, ok := oldByKey[key]; {
+		case !ok:
+			diff.Added = append(diff.Added, a)
+		case !oldAlert.Equal(a):
+			diff.Updated = append(diff.Updated, a)
+		}
+	}
+	for _, a := range oldAlerts {
+		if !newKeys[a.Key()] {
+			diff.Removed = append(diff.Removed, a)
+		}
+	}
+	return diff
+}
+
+// FilterAlerts returns the alerts that are kept by all filters.
+func FilterAlerts(alerts []*Alert, filters ...AlertFilter) []*Alert {
+	keep := func(a *Alert) bool {
+		for _, filter := range filters {
+			if !filter(a) {
+				return false
+			}
+		}
+		return true
+	}
+	var result []*Alert
+	for _, a := range alerts {
+		if a != nil && keep(a) {
+			result = append(result, a)
+		}
+	}
+	return result
+}
+
+// Empty returns whether d contains no changes.
+func (d *AlertDiff) Empty() bool {
+	return len(d.Added) == 0 && len(d.Updated) == 0 && len(d.Removed) == 0
+}
+
+// Equal returns whether a and b have equal fields. Times are compared with
+// time.Time.Equal.
+func (a *Alert) Equal(b *Alert) bool {
+	if a.Description != b.Description ||
+		a.Severity != b.Severity ||
+		a.Title != b.Title ||
+		a.URI != b.URI ||
+		!timesEqual(a.Expires, b.Expires) ||
+		!timesEqual(a.Time, b.Time) ||
+		len(a.Regions) != len(b.Regions) {
+		return false
+	}
+	for i, region := range a.Regions {
+		if region != b.Regions[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// ExpiredAt returns whether a has expired at t. Alerts without an expiry time
+// never expire.
+func (a *Alert) ExpiredAt(t time.Time) bool {
+	return a.Expires != nil && !a.Expires.IsZero() && !a.Expires.After(t)
+}
+
+// InRegion returns whether a applies to region. Regions are compared case
+// insensitively.
+func (a *Alert) InRegion(region string) bool {
+	for _, r := range a.Regions {
+		if strings.EqualFold(r, region) {
+			return true
+		}
+	}
+	return false
+}
+
+// Key returns a key that identifies a, composed of its URI and time.
+func (a *Alert) Key() string {
+	var sec int64
+	if a.Time != nil && !a.Time.IsZero() {
+		sec = a.Time.Unix()
+	}
+	return a.URI + "@" + strconv.FormatInt(sec, 10)
+}
+
+// AtLeast returns whether s is at least as severe as t.
+func (s Severity) AtLeast(t Severity) bool {
+	return s.Compare(t) >= 0
+}
+
+// Compare returns -1, 0, or 1 if s is less severe than, as severe as, or more
+// severe than t. Unknown severities are less severe than all known
+// severities.
+func (s Severity) Compare(t Severity) int {
+	sRank, tRank := severityRanks[s], severityRanks[t]
+	switch {
+	case sRank < tRank:
+		return -1
+	case sRank > tRank:
+		return 1
+	default:
+		return 0
+	}
+}
+
+func timesEqual(t1, t2 *Time) bool {
+	if t1 == nil || t2 == nil {
+		return t1 == t2
+	}
+	return t1.Equal(t2.Time)
+}
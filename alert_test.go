@@ -0,0 +1,103 @@
+package darksky_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/twpayne/go-darksky"
+)
+
+func TestSeverityCompare(t *testing.T) {
+	for _, tc := range []struct {
+		s, t     darksky.Severity
+		expected int
+	}{
+		{s: darksky.SeverityAdvisory, t: darksky.SeverityAdvisory, expected: 0},
+		{s: darksky.SeverityAdvisory, t: darksky.SeverityWatch, expected: -1},
+		{s: darksky.SeverityWarning, t: darksky.SeverityWatch, expected: 1},
+		{s: darksky.SeverityWarning, t: darksky.SeverityAdvisory, expected: 1},
+		{s: darksky.Severity("unknown"), t: darksky.SeverityAdvisory, expected: -1},
+		{s: darksky.Severity(""), t: darksky.Severity("unknown"), expected: 0},
+	} {
+		assert.Equal(t, tc.expected, tc.s.Compare(tc.t), "%s %s", tc.s, tc.t)
+		assert.Equal(t, tc.expected >= 0, tc.s.AtLeast(tc.t), "%s %s", tc.s, tc.t)
+	}
+}
+
+func TestFilterAlerts(t *testing.T) {
+	now := time.Unix(1556668800, 0)
+	advisory := &darksky.Alert{
+		Regions:  []string{"Los Angeles County"},
+		Severity: darksky.SeverityAdvisory,
+		Expires:  &darksky.Time{Time: now.Add(time.Hour)},
+	}
+	watch := &darksky.Alert{
+		Regions:  []string{"Ventura County", "Los Angeles County"},
+		Severity: darksky.SeverityWatch,
+		Expires:  &darksky.Time{Time: now},
+	}
+	warning := &darksky.Alert{
+		Regions:  []string{"Orange County"},
+		Severity: darksky.SeverityWarning,
+	}
+	alerts := []*darksky.Alert{advisory, nil, watch, warning}
+
+	assert.Equal(t, []*darksky.Alert{advisory, watch, warning}, darksky.FilterAlerts(alerts))
+	assert.Equal(t, []*darksky.Alert{watch, warning}, darksky.FilterAlerts(alerts, darksky.AlertSeverityAtLeast(darksky.SeverityWatch)))
+	assert.Equal(t, []*darksky.Alert{advisory, watch}, darksky.FilterAlerts(alerts, darksky.AlertInRegion("los angeles county")))
+	assert.Equal(t, []*darksky.Alert{advisory, warning}, darksky.FilterAlerts(alerts, darksky.AlertActiveAt(now)))
+	assert.Equal(t, []*darksky.Alert{advisory}, darksky.FilterAlerts(alerts, darksky.AlertActiveAt(now), darksky.AlertInRegion("Los Angeles County")))
+	assert.Nil(t, darksky.FilterAlerts(alerts, darksky.AlertInRegion("Kern County")))
+}
+
+func TestAlertKey(t *testing.T) {
+	t0 := time.Unix(1556668800, 0)
+	a1 := &darksky.Alert{URI: "https://alerts.weather.gov/1", Time: &darksky.Time{Time: t0}}
+	a2 := &darksky.Alert{URI: "https://alerts.weather.gov/1", Time: &darksky.Time{Time: t0.In(time.FixedZone("", -7*60*60))}, Title: "Updated"}
+	a3 := &darksky.Alert{URI: "https://alerts.weather.gov/1", Time: &darksky.Time{Time: t0.Add(time.Hour)}}
+	a4 := &darksky.Alert{URI: "https://alerts.weather.gov/1"}
+	assert.Equal(t, a1.Key(), a2.Key())
+	assert.NotEqual(t, a1.Key(), a3.Key())
+	assert.NotEqual(t, a1.Key(), a4.Key())
+	assert.Equal(t, []*darksky.Alert{a1, a3, a4}, darksky.DedupeAlerts([]*darksky.Alert{a1, a2, nil, a3, a4}))
+}
+
+func TestDiffAlerts(t *testing.T) {
+	t0 := time.Unix(1556668800, 0)
+	alert := func(uri, title string, expires time.Time) *darksky.Alert {
+		return &darksky.Alert{
+			Expires:  &darksky.Time{Time: expires},
+			Regions:  []string{"Los Angeles County"},
+			Severity: darksky.SeverityWarning,
+			Time:     &darksky.Time{Time: t0},
+			Title:    title,
+			URI:      uri,
+		}
+	}
+	unchangedOld := alert("https://alerts.weather.gov/1", "Heat", t0.Add(time.Hour))
+	unchangedNew := alert("https://alerts.weather.gov/1", "Heat", t0.Add(time.Hour).In(time.FixedZone("PDT", -7*60*60)))
+	updatedOld := alert("https://alerts.weather.gov/2", "Wind", t0.Add(time.Hour))
+	updatedNew := alert("https://alerts.weather.gov/2", "Wind", t0.Add(2*time.Hour))
+	removed := alert("https://alerts.weather.gov/3", "Fog", t0.Add(time.Hour))
+	added := alert("https://alerts.weather.gov/4", "Fire", t0.Add(time.Hour))
+
+	diff := darksky.DiffAlerts(
+		[]*darksky.Alert{unchangedOld, updatedOld, removed},
+		[]*darksky.Alert{added, updatedNew, unchangedNew, added},
+	)
+	assert.Equal(t, &darksky.AlertDiff{
+		Added:   []*darksky.Alert{added},
+		Updated: []*darksky.Alert{updatedNew},
+		Removed: []*darksky.Alert{removed},
+	}, diff)
+	assert.False(t, diff.Empty())
+
+	assert.True(t, darksky.DiffAlerts(nil, nil).Empty())
+	assert.True(t, darksky.DiffAlerts([]*darksky.Alert{unchangedOld}, []*darksky.Alert{unchangedNew}).Empty())
+
+	regionsChanged := alert("https://alerts.weather.gov/1", "Heat", t0.Add(time.Hour))
+	regionsChanged.Regions = append(regionsChanged.Regions, "Ventura County")
+	assert.Equal(t, []*darksky.Alert{regionsChanged}, darksky.DiffAlerts([]*darksky.Alert{unchangedOld}, []*darksky.Alert{regionsChanged}).Updated)
+}
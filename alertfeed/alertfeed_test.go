@@ -0,0 +1,345 @@
+package alertfeed_test
+
+import (
+	"bytes"
+	"encoding/xml"
+	"io"
+	"net/url"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	darksky "github.com/twpayne/go-darksky"
+	"github.com/twpayne/go-darksky/alertfeed"
+)
+
+// A node is a parsed XML element.
+type node struct {
+	name     xml.Name
+	attrs    map[string]string
+	text     string
+	children []*node
+}
+
+// A schemaElement describes an element in an XML schema. Children must appear
+// in order. Text, if not nil, must match the element's text.
+type schemaElement struct {
+	name     string
+	min, max int // max of -1 is unbounded.
+	text     *regexp.Regexp
+	children []*schemaElement
+}
+
+var (
+	capDateTimeRx   = regexp.MustCompile(`\A\d{4}-\d\d-\d\dT\d\d:\d\d:\d\d(-\d\d:\d\d|\+(0[1-9]|1\d):\d\d|\+00:(0[1-9]|[1-5]\d))\z`) // UTC must be -00:00.
+	capIdentifierRx = regexp.MustCompile(`\A[^ ,<&]+\z`)
+	capCircleRx     = regexp.MustCompile(`\A-?\d+(\.\d+)?,-?\d+(\.\d+)? \d+(\.\d+)?\z`)
+	rfc3339Rx       = regexp.MustCompile(`\A\d{4}-\d\d-\d\dT\d\d:\d\d:\d\d(\.\d+)?(Z|[-+]\d\d:\d\d)\z`)
+	anyRx           = regexp.MustCompile(`(?s).*`)
+)
+
+func enum(values ...string) *regexp.Regexp {
+	return regexp.MustCompile(`\A(` + strings.Join(values, "|") + `)\z`)
+}
+
+// capSchema is the structure of a CAP 1.2 alert, from
+// http://docs.oasis-open.org/emergency/cap/v1.2/CAP-v1.2.xsd.
+var capSchema = &schemaElement{
+	name: "alert", min: 1, max: 1,
+	children: []*schemaElement{
+		{name: "identifier", min: 1, max: 1, text: capIdentifierRx},
+		{name: "sender", min: 1, max: 1, text: capIdentifierRx},
+		{name: "sent", min: 1, max: 1, text: capDateTimeRx},
+		{name: "status", min: 1, max: 1, text: enum("Actual", "Exercise", "System", "Test", "Draft")},
+		{name: "msgType", min: 1, max: 1, text: enum("Alert", "Update", "Cancel", "Ack", "Error")},
+		{name: "source", max: 1},
+		{name: "scope", min: 1, max: 1, text: enum("Public", "Restricted", "Private")},
+		{name: "restriction", max: 1},
+		{name: "addresses", max: 1},
+		{name: "code", max: -1},
+		{name: "note", max: 1},
+		{name: "references", max: 1},
+		{name: "incidents", max: 1},
+		{
+			name: "info", max: -1,
+			children: []*schemaElement{
+				{name: "language", max: 1},
+				{name: "category", min: 1, max: -1, text: enum("Geo", "Met", "Safety", "Security", "Rescue", "Fire", "Health", "Env", "Transport", "Infra", "CBRNE", "Other")},
+				{name: "event", min: 1, max: 1, text: anyRx},
+				{name: "responseType", max: -1},
+				{name: "urgency", min: 1, max: 1, text: enum("Immediate", "Expected", "Future", "Past", "Unknown")},
+				{name: "severity", min: 1, max: 1, text: enum("Extreme", "Severe", "Moderate", "Minor", "Unknown")},
+				{name: "certainty", min: 1, max: 1, text: enum("Observed", "Likely", "Possible", "Unlikely", "Unknown")},
+				{name: "audience", max: 1},
+				{name: "eventCode", max: -1},
+				{name: "effective", max: 1, text: capDateTimeRx},
+				{name: "onset", max: 1, text: capDateTimeRx},
+				{name: "expires", max: 1, text: capDateTimeRx},
+				{name: "senderName", max: 1, text: anyRx},
+				{name: "headline", max: 1, text: anyRx},
+				{name: "description", max: 1, text: anyRx},
+				{name: "instruction", max: 1},
+				{name: "web", max: 1, text: anyRx},
+				{name: "contact", max: 1},
+				{name: "parameter", max: -1},
+				{name: "resource", max: -1},
+				{
+					name: "area", max: -1,
+					children: []*schemaElement{
+						{name: "areaDesc", min: 1, max: 1, text: anyRx},
+						{name: "polygon", max: -1},
+						{name: "circle", max: -1, text: capCircleRx},
+						{name: "geocode", max: -1},
+						{name: "altitude", max: 1},
+						{name: "ceiling", max: 1},
+					},
+				},
+			},
+		},
+	},
+}
+
+func parseXML(t *testing.T, data []byte) *node {
+	d := xml.NewDecoder(bytes.NewReader(data))
+	var stack []*node
+	var root *node
+	for {
+		token, err := d.Token()
+		if err == io.EOF {
+			break
+		}
+		require.NoError(t, err)
+		switch token := token.(type) {
+		case xml.StartElement:
+			n := &node{
+				name:  token.Name,
+				attrs: make(map[string]string),
+			}
+			for _, attr := range token.Attr {
+				if attr.Name.Space == "" && attr.Name.Local != "xmlns" {
+					n.attrs[attr.Name.Local] = attr.Value
+				}
+			}
+			if len(stack) == 0 {
+				root = n
+			} else {
+				parent := stack[len(stack)-1]
+				parent.children = append(parent.children, n)
+			}
+			stack = append(stack, n)
+		case xml.CharData:
+			if len(stack) != 0 {
+				stack[len(stack)-1].text += string(token)
+			}
+		case xml.EndElement:
+			stack = stack[:len(stack)-1]
+		}
+	}
+	require.NotNil(t, root)
+	return root
+}
+
+// validate validates n and its children against e.
+func validate(t *testing.T, path string, n *node, namespace string, e *schemaElement) {
+	path += "/" + e.name
+	assert.Equal(t, xml.Name{Space: namespace, Local: e.name}, n.name, path)
+	if e.text != nil {
+		assert.Regexp(t, e.text, n.text, path)
+	}
+	i := 0
+	for _, child := range e.children {
+		count := 0
+		for i < len(n.children) && n.children[i].name.Local == child.name {
+			validate(t, path, n.children[i], namespace, child)
+			i++
+			count++
+		}
+		assert.True(t, count >= child.min, "%s/%s: expected at least %d, got %d", path, child.name, child.min, count)
+		assert.True(t, child.max == -1 || count <= child.max, "%s/%s: expected at most %d, got %d", path, child.name, child.max, count)
+	}
+	if e.children != nil && i < len(n.children) {
+		assert.Fail(t, "unexpected element", "%s/%s", path, n.children[i].name.Local)
+	}
+}
+
+// validateAtom validates feed against the constraints of RFC 4287.
+func validateAtom(t *testing.T, feed *node) {
+	const atom = alertfeed.AtomNamespace
+	assert.Equal(t, xml.Name{Space: atom, Local: "feed"}, feed.name)
+	count := func(n *node, name string) int {
+		result := 0
+		for _, child := range n.children {
+			if child.name == (xml.Name{Space: atom, Local: name}) {
+				result++
+			}
+		}
+		return result
+	}
+	validateCommon := func(path string, n *node) {
+		for _, name := range []string{"id", "title", "updated"} {
+			assert.Equal(t, 1, count(n, name), "%s/%s", path, name)
+		}
+		for _, child := range n.children {
+			switch {
+			case child.name.Space == alertfeed.GeoRSSNamespace && child.name.Local == "point":
+				assert.Regexp(t, `\A-?\d+(\.\d+)? -?\d+(\.\d+)?\z`, child.text)
+			case child.name.Space != atom:
+				assert.Fail(t, "unexpected element", "%s/%s", path, child.name.Local)
+			case child.name.Local == "id":
+				u, err := url.Parse(child.text)
+				assert.NoError(t, err)
+				assert.NotEmpty(t, u.Scheme, "%s/id", path)
+			case child.name.Local == "updated", child.name.Local == "published":
+				assert.Regexp(t, rfc3339Rx, child.text, "%s/%s", path, child.name.Local)
+			case child.name.Local == "link":
+				assert.NotEmpty(t, child.attrs["href"], "%s/link", path)
+			case child.name.Local == "category":
+				assert.NotEmpty(t, child.attrs["term"], "%s/category", path)
+			case child.name.Local == "author":
+				assert.Equal(t, 1, count(child, "name"), "%s/author/name", path)
+			}
+		}
+	}
+	validateCommon("/feed", feed)
+	assert.Equal(t, 1, count(feed, "author"))
+	for _, entry := range feed.children {
+		if entry.name.Local != "entry" {
+			continue
+		}
+		validateCommon("/feed/entry", entry)
+		for _, name := range []string{"content", "published", "summary"} {
+			assert.True(t, count(entry, name) <= 1, "/feed/entry/%s", name)
+		}
+		hasAlternateLink := false
+		for _, child := range entry.children {
+			if child.name.Local == "link" && child.attrs["rel"] == "alternate" {
+				hasAlternateLink = true
+			}
+		}
+		assert.True(t, hasAlternateLink || count(entry, "content") == 1, "/feed/entry: content or alternate link required")
+	}
+}
+
+func testAlerts() []*darksky.Alert {
+	pdt := time.FixedZone("PDT", -7*60*60)
+	return []*darksky.Alert{
+		{
+			Description: "* WHAT...Temperatures up to 105 expected.\n* WHERE...Los Angeles County.",
+			Expires:     &darksky.Time{Time: time.Date(2019, time.May, 2, 20, 0, 0, 0, pdt)},
+			Regions:     []string{"Los Angeles County", "Ventura County"},
+			Severity:    darksky.SeverityWarning,
+			Time:        &darksky.Time{Time: time.Date(2019, time.May, 1, 3, 0, 0, 0, pdt)},
+			Title:       "Excessive Heat Warning",
+			URI:         "https://alerts.weather.gov/cap/wwacapget.php?x=CA1259&y=2",
+		},
+		{
+			Expires:  &darksky.Time{Time: time.Date(2019, time.May, 1, 18, 0, 0, 0, time.UTC)},
+			Severity: darksky.SeverityAdvisory,
+			Time:     &darksky.Time{Time: time.Date(2019, time.May, 1, 10, 0, 0, 0, time.UTC)},
+			Title:    "Dense Fog Advisory",
+		},
+		{
+			Title: "Special Weather Statement",
+		},
+	}
+}
+
+func TestCAP(t *testing.T) {
+	alerts := testAlerts()
+	capAlerts := alertfeed.NewCAPAlerts(append(alerts, nil), 34.0219, -118.4814, "alerts@example.com")
+	require.Len(t, capAlerts, len(alerts))
+	for i, capAlert := range capAlerts {
+		b := &bytes.Buffer{}
+		require.NoError(t, alertfeed.EncodeCAP(b, capAlert))
+		assert.True(t, strings.HasPrefix(b.String(), xml.Header))
+		validate(t, "", parseXML(t, b.Bytes()), alertfeed.CAPNamespace, capSchema)
+
+		var decoded alertfeed.CAPAlert
+		require.NoError(t, xml.Unmarshal(b.Bytes(), &decoded))
+		decoded.XMLName = xml.Name{}
+		assert.Equal(t, capAlert, &decoded, i)
+	}
+
+	warning := capAlerts[0]
+	assert.Equal(t, "2019-05-01T03:00:00-07:00", warning.Sent)
+	assert.Equal(t, "https://alerts.weather.gov/cap/wwacapget.php?x=CA1259%26y=2@1556704800", warning.Identifier)
+	require.Len(t, warning.Info, 1)
+	info := warning.Info[0]
+	assert.Equal(t, "Excessive Heat Warning", info.Event)
+	assert.Equal(t, "Expected", info.Urgency)
+	assert.Equal(t, "Severe", info.Severity)
+	assert.Equal(t, "Likely", info.Certainty)
+	assert.Equal(t, "2019-05-02T20:00:00-07:00", info.Expires)
+	assert.Equal(t, "https://alerts.weather.gov/cap/wwacapget.php?x=CA1259&y=2", info.Web)
+	assert.Equal(t, []*alertfeed.CAPArea{
+		{
+			AreaDesc: "Los Angeles County, Ventura County",
+			Circle:   []string{"34.0219,-118.4814 0"},
+		},
+	}, info.Area)
+
+	advisory := capAlerts[1]
+	assert.Equal(t, "2019-05-01T10:00:00-00:00", advisory.Sent)
+	assert.Equal(t, "Minor", advisory.Info[0].Severity)
+	assert.Equal(t, "2019-05-01T10:00:00-00:00", advisory.Info[0].Effective)
+	assert.Equal(t, "2019-05-01T18:00:00-00:00", advisory.Info[0].Expires)
+	assert.Equal(t, "34.0219,-118.4814", advisory.Info[0].Area[0].AreaDesc)
+
+	statement := capAlerts[2]
+	assert.True(t, strings.HasSuffix(statement.Sent, "-00:00"), statement.Sent)
+	assert.Equal(t, "", statement.Info[0].Expires)
+	assert.Equal(t, "Unknown", statement.Info[0].Urgency)
+	assert.Equal(t, "Unknown", statement.Info[0].Severity)
+	assert.Equal(t, "Unknown", statement.Info[0].Certainty)
+}
+
+func TestAtom(t *testing.T) {
+	alerts := testAlerts()
+	feed := alertfeed.NewAtomFeed(alerts, 34.0219, -118.4814, nil)
+	b := &bytes.Buffer{}
+	require.NoError(t, alertfeed.EncodeAtom(b, feed))
+	validateAtom(t, parseXML(t, b.Bytes()))
+
+	assert.Equal(t, "Weather alerts for 34.0219,-118.4814", feed.Title)
+	assert.Equal(t, "Dark Sky", feed.Author.Name)
+	assert.Equal(t, "2019-05-01T03:00:00-07:00", feed.Updated)
+	assert.Equal(t, "34.0219 -118.4814", feed.Point)
+	require.Len(t, feed.Entries, 3)
+	assert.Equal(t, "2019-05-01T03:00:00-07:00", feed.Entries[0].Published)
+	assert.Equal(t, []*alertfeed.AtomLink{
+		{Href: "https://alerts.weather.gov/cap/wwacapget.php?x=CA1259&y=2", Rel: "alternate"},
+	}, feed.Entries[0].Links)
+	assert.Equal(t, []*alertfeed.AtomCategory{{Term: "warning"}}, feed.Entries[0].Categories)
+	assert.Nil(t, feed.Entries[0].Content)
+	assert.NotNil(t, feed.Entries[1].Content)
+	assert.Equal(t, "", feed.Entries[2].Published)
+	assert.NotEmpty(t, feed.Entries[2].Updated)
+
+	// Entry IDs are stable across updates to the same alert.
+	alerts[0].Expires = &darksky.Time{Time: alerts[0].Expires.Add(time.Hour)}
+	updatedFeed := alertfeed.NewAtomFeed(alerts, 34.0219, -118.4814, nil)
+	assert.Equal(t, feed.ID, updatedFeed.ID)
+	assert.Equal(t, feed.Entries[0].ID, updatedFeed.Entries[0].ID)
+	assert.NotEqual(t, feed.Entries[0].ID, feed.Entries[1].ID)
+
+	customFeed := alertfeed.NewAtomFeed(nil, 34.0219, -118.4814, &alertfeed.AtomOptions{
+		ID:     "https://example.com/alerts",
+		Title:  "Santa Monica alerts",
+		Author: "Example",
+		Link:   "https://example.com/alerts.atom",
+	})
+	b.Reset()
+	require.NoError(t, alertfeed.EncodeAtom(b, customFeed))
+	validateAtom(t, parseXML(t, b.Bytes()))
+	assert.Equal(t, "https://example.com/alerts", customFeed.ID)
+	assert.Equal(t, "Santa Monica alerts", customFeed.Title)
+	assert.Equal(t, "Example", customFeed.Author.Name)
+	assert.Equal(t, []*alertfeed.AtomLink{
+		{Href: "https://example.com/alerts.atom", Rel: "self", Type: "application/atom+xml"},
+	}, customFeed.Links)
+	assert.Empty(t, customFeed.Entries)
+}
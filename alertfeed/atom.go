@@ -0,0 +1,179 @@
+package alertfeed
+
+import (
+	"crypto/sha1" //nolint: gosec
+	"encoding/hex"
+	"encoding/xml"
+	"io"
+	"time"
+
+	darksky "github.com/twpayne/go-darksky"
+)
+
+// Namespaces.
+const (
+	AtomNamespace   = "http://www.w3.org/2005/Atom"
+	GeoRSSNamespace = "http://www.georss.org/georss"
+)
+
+// An AtomFeed is an Atom feed, see https://tools.ietf.org/html/rfc4287.
+// Coordinates are encoded as GeoRSS Simple points.
+type AtomFeed struct {
+	XMLName xml.Name     `xml:"http://www.w3.org/2005/Atom feed"`
+	ID      string       `xml:"id"`
+	Title   string       `xml:"title"`
+	Updated string       `xml:"updated"`
+	Author  *AtomPerson  `xml:"author"`
+	Links   []*AtomLink  `xml:"link"`
+	Point   string       `xml:"http://www.georss.org/georss point,omitempty"`
+	Entries []*AtomEntry `xml:"entry"`
+}
+
+// An AtomEntry is an Atom entry.
+type AtomEntry struct {
+	ID         string          `xml:"id"`
+	Title      string          `xml:"title"`
+	Updated    string          `xml:"updated"`
+	Published  string          `xml:"published,omitempty"`
+	Summary    string          `xml:"summary,omitempty"`
+	Content    *AtomContent    `xml:"content"`
+	Links      []*AtomLink     `xml:"link"`
+	Categories []*AtomCategory `xml:"category"`
+	Point      string          `xml:"http://www.georss.org/georss point,omitempty"`
+}
+
+// An AtomContent is the content of an Atom entry.
+type AtomContent struct {
+	Type string `xml:"type,attr"`
+	Text string `xml:",chardata"`
+}
+
+// An AtomPerson is an Atom person.
+type AtomPerson struct {
+	Name string `xml:"name"`
+}
+
+// An AtomLink is an Atom link.
+type AtomLink struct {
+	Href string `xml:"href,attr"`
+	Rel  string `xml:"rel,attr,omitempty"`
+	Type string `xml:"type,attr,omitempty"`
+}
+
+// An AtomCategory is an Atom category.
+type AtomCategory struct {
+	Term string `xml:"term,attr"`
+}
+
+// AtomOptions are options for an Atom feed.
+type AtomOptions struct {
+	ID     string // Defaults to a URN derived from the coordinates.
+	Title  string // Defaults to "Weather alerts for <latitude>,<longitude>".
+	Author string // Defaults to "Dark Sky".
+	Link   string // The feed's own URL, if any.
+}
+
+// NewAtomFeed returns a new Atom feed containing an entry for each of alerts,
+// which apply at latitude and longitude. Each entry's ID is derived from its
+// alert's key, so updated alerts update the existing entry in feed readers.
+// The feed is updated at the latest alert's time, or the current time if there
+// are no alerts with times.
+func NewAtomFeed(alerts []*darksky.Alert, latitude, longitude float64, options *AtomOptions) *AtomFeed {
+	if options == nil {
+		options = &AtomOptions{}
+	}
+	point := formatCoordinates(latitude, longitude, " ")
+	feed := &AtomFeed{
+		ID:     options.ID,
+		Title:  options.Title,
+		Author: &AtomPerson{Name: options.Author},
+		Point:  point,
+	}
+	if feed.ID == "" {
+		feed.ID = uuidURN("darksky-alerts:" + formatCoordinates(latitude, longitude, ","))
+	}
+	if feed.Title == "" {
+		feed.Title = "Weather alerts for " + formatCoordinates(latitude, longitude, ",")
+	}
+	if feed.Author.Name == "" {
+		feed.Author.Name = "Dark Sky"
+	}
+	if options.Link != "" {
+		feed.Links = append(feed.Links, &AtomLink{
+			Href: options.Link,
+			Rel:  "self",
+			Type: "application/atom+xml",
+		})
+	}
+
+	var updated time.Time
+	for _, a := range alerts {
+		if a == nil {
+			continue
+		}
+		var published time.Time
+		if a.Time != nil {
+			published = a.Time.Time
+		}
+		if published.After(updated) {
+			updated = published
+		}
+		entry := &AtomEntry{
+			ID:        uuidURN(a.Key()),
+			Title:     a.Title,
+			Published: formatAtomTime(published),
+			Summary:   a.Description,
+			Point:     point,
+		}
+		if entry.Updated = entry.Published; entry.Updated == "" {
+			entry.Updated = formatAtomTime(time.Now())
+		}
+		if a.URI != "" {
+			entry.Links = append(entry.Links, &AtomLink{
+				Href: a.URI,
+				Rel:  "alternate",
+			})
+		} else {
+			// Entries without an alternate link must have content.
+			entry.Content = &AtomContent{
+				Type: "text",
+				Text: a.Description,
+			}
+		}
+		if a.Severity != "" {
+			entry.Categories = append(entry.Categories, &AtomCategory{
+				Term: string(a.Severity),
+			})
+		}
+		feed.Entries = append(feed.Entries, entry)
+	}
+	if updated.IsZero() {
+		updated = time.Now()
+	}
+	feed.Updated = formatAtomTime(updated)
+
+	return feed
+}
+
+// EncodeAtom writes feed as an indented Atom XML document to w.
+func EncodeAtom(w io.Writer, feed *AtomFeed) error {
+	return encodeXML(w, feed)
+}
+
+func formatAtomTime(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.Format(time.RFC3339)
+}
+
+// uuidURN returns a URN containing a UUID derived from the SHA-1 hash of name,
+// formatted as a name-based (version 5) UUID.
+func uuidURN(name string) string {
+	sum := sha1.Sum([]byte(name)) //nolint: gosec
+	uuid := sum[:16]
+	uuid[6] = uuid[6]&0x0f | 0x50
+	uuid[8] = uuid[8]&0x3f | 0x80
+	s := hex.EncodeToString(uuid)
+	return "urn:uuid:" + s[0:8] + "-" + s[8:12] + "-" + s[12:16] + "-" + s[16:20] + "-" + s[20:32]
+}
@@ -0,0 +1,178 @@
+// Package alertfeed encodes Dark Sky alerts as Common Alerting Protocol
+// (CAP) 1.2 documents and Atom feeds.
+package alertfeed
+
+import (
+	"encoding/xml"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	darksky "github.com/twpayne/go-darksky"
+)
+
+// CAPNamespace is the CAP 1.2 XML namespace.
+const CAPNamespace = "urn:oasis:names:tc:emergency:cap:1.2"
+
+// CAP time layouts. CAP times must have a numeric time zone, and UTC must be
+// written as -00:00.
+const (
+	capTimeLayout    = "2006-01-02T15:04:05-07:00"
+	capUTCTimeLayout = "2006-01-02T15:04:05"
+	capUTCSuffix     = "-00:00"
+)
+
+// A CAPAlert is a CAP 1.2 alert, see
+// http://docs.oasis-open.org/emergency/cap/v1.2/CAP-v1.2-os.html.
+type CAPAlert struct {
+	XMLName    xml.Name   `xml:"urn:oasis:names:tc:emergency:cap:1.2 alert"`
+	Identifier string     `xml:"identifier"`
+	Sender     string     `xml:"sender"`
+	Sent       string     `xml:"sent"`
+	Status     string     `xml:"status"`
+	MsgType    string     `xml:"msgType"`
+	Scope      string     `xml:"scope"`
+	Info       []*CAPInfo `xml:"info"`
+}
+
+// A CAPInfo is a CAP 1.2 info element.
+type CAPInfo struct {
+	Language    string     `xml:"language,omitempty"`
+	Category    []string   `xml:"category"`
+	Event       string     `xml:"event"`
+	Urgency     string     `xml:"urgency"`
+	Severity    string     `xml:"severity"`
+	Certainty   string     `xml:"certainty"`
+	Effective   string     `xml:"effective,omitempty"`
+	Expires     string     `xml:"expires,omitempty"`
+	SenderName  string     `xml:"senderName,omitempty"`
+	Headline    string     `xml:"headline,omitempty"`
+	Description string     `xml:"description,omitempty"`
+	Web         string     `xml:"web,omitempty"`
+	Area        []*CAPArea `xml:"area"`
+}
+
+// A CAPArea is a CAP 1.2 area element.
+type CAPArea struct {
+	AreaDesc string   `xml:"areaDesc"`
+	Circle   []string `xml:"circle"`
+}
+
+// capSeverities map Dark Sky severities to CAP urgencies, severities, and
+// certainties, following the US National Weather Service's usage.
+var capSeverities = map[darksky.Severity]struct {
+	urgency, severity, certainty string
+}{
+	darksky.SeverityAdvisory: {urgency: "Expected", severity: "Minor", certainty: "Likely"},
+	darksky.SeverityWatch:    {urgency: "Future", severity: "Severe", certainty: "Possible"},
+	darksky.SeverityWarning:  {urgency: "Expected", severity: "Severe", certainty: "Likely"},
+}
+
+// NewCAPAlert returns a new CAP alert from a, which applies at latitude and
+// longitude, sent by sender. The alert's identifier is derived from a's key
+// and it is sent at a's time, or the current time if a has no time. Alerts
+// with unknown severities have unknown urgency, severity, and certainty.
+func NewCAPAlert(a *darksky.Alert, latitude, longitude float64, sender string) *CAPAlert {
+	sent := time.Now().UTC()
+	if a.Time != nil && !a.Time.IsZero() {
+		sent = a.Time.Time
+	}
+	urgency, severity, certainty := "Unknown", "Unknown", "Unknown"
+	if s, ok := capSeverities[a.Severity]; ok {
+		urgency, severity, certainty = s.urgency, s.severity, s.certainty
+	}
+	areaDesc := strings.Join(a.Regions, ", ")
+	if areaDesc == "" {
+		areaDesc = formatCoordinates(latitude, longitude, ",")
+	}
+	return &CAPAlert{
+		Identifier: capIdentifierReplacer.Replace(a.Key()),
+		Sender:     sender,
+		Sent:       formatCAPTime(sent),
+		Status:     "Actual",
+		MsgType:    "Alert",
+		Scope:      "Public",
+		Info: []*CAPInfo{
+			{
+				Category:    []string{"Met"},
+				Event:       a.Title,
+				Urgency:     urgency,
+				Severity:    severity,
+				Certainty:   certainty,
+				Effective:   formatOptionalCAPTime(a.Time),
+				Expires:     formatOptionalCAPTime(a.Expires),
+				Headline:    a.Title,
+				Description: a.Description,
+				Web:         a.URI,
+				Area: []*CAPArea{
+					{
+						AreaDesc: areaDesc,
+						Circle:   []string{formatCoordinates(latitude, longitude, ",") + " 0"},
+					},
+				},
+			},
+		},
+	}
+}
+
+// NewCAPAlerts returns a new CAP alert for each of alerts, which apply at
+// latitude and longitude, sent by sender.
+func NewCAPAlerts(alerts []*darksky.Alert, latitude, longitude float64, sender string) []*CAPAlert {
+	capAlerts := make([]*CAPAlert, 0, len(alerts))
+	for _, a := range alerts {
+		if a != nil {
+			capAlerts = append(capAlerts, NewCAPAlert(a, latitude, longitude, sender))
+		}
+	}
+	return capAlerts
+}
+
+// EncodeCAP writes a as an indented CAP 1.2 XML document to w.
+func EncodeCAP(w io.Writer, a *CAPAlert) error {
+	return encodeXML(w, a)
+}
+
+// capIdentifierReplacer replaces characters that are not permitted in CAP
+// identifiers.
+var capIdentifierReplacer = strings.NewReplacer(
+	" ", "%20",
+	",", "%2C",
+	"<", "%3C",
+	"&", "%26",
+)
+
+func encodeXML(w io.Writer, v interface{}) error {
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	e := xml.NewEncoder(w)
+	e.Indent("", "  ")
+	if err := e.Encode(v); err != nil {
+		return err
+	}
+	_, err := io.WriteString(w, "\n")
+	return err
+}
+
+// formatCAPTime formats t as a CAP time. Times with a zero offset are written
+// with -00:00, as required by CAP 1.2.
+func formatCAPTime(t time.Time) string {
+	if _, offset := t.Zone(); offset == 0 {
+		return t.Format(capUTCTimeLayout) + capUTCSuffix
+	}
+	return t.Format(capTimeLayout)
+}
+
+// formatOptionalCAPTime formats t as a CAP time, or returns the empty string if
+// t is nil or zero.
+func formatOptionalCAPTime(t *darksky.Time) string {
+	if t == nil || t.IsZero() {
+		return ""
+	}
+	return formatCAPTime(t.Time)
+}
+
+func formatCoordinates(latitude, longitude float64, sep string) string {
+	return strconv.FormatFloat(latitude, 'f', -1, 64) + sep + strconv.FormatFloat(longitude, 'f', -1, 64)
+}
@@ -0,0 +1,217 @@
+package darksky
+
+import (
+	"math"
+	"time"
+)
+
+// A MoonPhaseName is the name of a phase of the moon.
+type MoonPhaseName string
+
+// Moon phase names.
+const (
+	MoonPhaseNew            MoonPhaseName = "new moon"
+	MoonPhaseWaxingCrescent MoonPhaseName = "waxing crescent"
+	MoonPhaseFirstQuarter   MoonPhaseName = "first quarter"
+	MoonPhaseWaxingGibbous  MoonPhaseName = "waxing gibbous"
+	MoonPhaseFull           MoonPhaseName = "full moon"
+	MoonPhaseWaningGibbous  MoonPhaseName = "waning gibbous"
+	MoonPhaseLastQuarter    MoonPhaseName = "last quarter"
+	MoonPhaseWaningCrescent MoonPhaseName = "waning crescent"
+)
+
+// moonPhaseNames are the moon phase names, in order from new moon.
+var moonPhaseNames = []MoonPhaseName{
+	MoonPhaseNew,
+	MoonPhaseWaxingCrescent,
+	MoonPhaseFirstQuarter,
+	MoonPhaseWaxingGibbous,
+	MoonPhaseFull,
+	MoonPhaseWaningGibbous,
+	MoonPhaseLastQuarter,
+	MoonPhaseWaningCrescent,
+}
+
+// Solar elevations, in degrees, of solar events. The elevation of sunrise and
+// sunset accounts for atmospheric refraction and the radius of the sun.
+const (
+	elevationSunriseSunset = -0.833
+	elevationCivil         = -6
+	elevationNautical      = -12
+	elevationAstronomical  = -18
+)
+
+// A SolarCrossing is when the sun crosses an elevation during a day. Rise and
+// Set are nil if the sun does not cross the elevation, in which case exactly
+// one of AlwaysAbove and AlwaysBelow is true. For example, during polar day
+// the sun is always above the horizon and there is no sunrise or sunset.
+type SolarCrossing struct {
+	Rise        *Time
+	Set         *Time
+	AlwaysAbove bool
+	AlwaysBelow bool
+}
+
+// SolarEvents are the solar events of a day. CivilTwilight,
+// NauticalTwilight, and AstronomicalTwilight's Rise and Set are the
+// corresponding dawns and dusks.
+type SolarEvents struct {
+	SolarNoon            *Time
+	Sun                  SolarCrossing
+	CivilTwilight        SolarCrossing
+	NauticalTwilight     SolarCrossing
+	AstronomicalTwilight SolarCrossing
+}
+
+// MoonIllumination returns the fraction of the moon's disc that is
+// illuminated, between 0 and 1.
+func (d *DailyData) MoonIllumination() float64 {
+	return (1 - math.Cos(2*math.Pi*d.MoonPhase)) / 2
+}
+
+// MoonPhaseName returns the name of d's moon phase. The new moon, first
+// quarter, full moon, and last quarter phases are centered on moon phases 0,
+// 0.25, 0.5, and 0.75 respectively and are each one eighth of a lunation long.
+func (d *DailyData) MoonPhaseName() MoonPhaseName {
+	n := len(moonPhaseNames)
+	i := int(math.Floor(d.MoonPhase*float64(n)+0.5)) % n
+	if i < 0 {
+		i += n
+	}
+	return moonPhaseNames[i]
+}
+
+// SolarEvents returns the solar events at f's latitude and longitude on the
+// day containing t in f's location.
+func (f *Forecast) SolarEvents(t time.Time) *SolarEvents {
+	return SolarEventsAt(f.Latitude, f.Longitude, t.In(f.Location()))
+}
+
+// SolarEventsAt returns the solar events at latitude and longitude on the day
+// containing t in t's location. Times are calculated offline using the US
+// National Oceanic and Atmospheric Administration's algorithm, see
+// https://gml.noaa.gov/grad/solcalc/calcdetails.html, and are accurate to
+// about a minute for latitudes between ±72°.
+func SolarEventsAt(latitude, longitude float64, t time.Time) *SolarEvents {
+	year, month, day := t.Date()
+	midnightUTC := time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+	jd := float64(midnightUTC.Unix())/86400 + 2440587.5
+	toTime := func(minutes float64) *Time {
+		d := time.Duration(math.Round(minutes * float64(time.Minute)))
+		return &Time{Time: midnightUTC.Add(d).In(t.Location())}
+	}
+
+	// Solar noon.
+	eqTime := equationOfTime(julianCentury(jd - longitude/360))
+	noon := 720 - 4*longitude - eqTime
+	eqTime = equationOfTime(julianCentury(jd + noon/1440))
+	noon = 720 - 4*longitude - eqTime
+
+	crossing := func(elevation float64) SolarCrossing {
+		ha, ok := hourAngle(latitude, declination(julianCentury(jd+noon/1440)), elevation)
+		if !ok {
+			return SolarCrossing{
+				AlwaysAbove: ha > 0,
+				AlwaysBelow: ha < 0,
+			}
+		}
+		event := func(sign float64) float64 {
+			minutes := noon + sign*4*ha
+			for i := 0; i < 2; i++ {
+				tc := julianCentury(jd + minutes/1440)
+				if ha, ok := hourAngle(latitude, declination(tc), elevation); ok {
+					minutes = 720 - 4*(longitude-sign*ha) - equationOfTime(tc)
+				}
+			}
+			return minutes
+		}
+		return SolarCrossing{
+			Rise: toTime(event(-1)),
+			Set:  toTime(event(1)),
+		}
+	}
+
+	return &SolarEvents{
+		SolarNoon:            toTime(noon),
+		Sun:                  crossing(elevationSunriseSunset),
+		CivilTwilight:        crossing(elevationCivil),
+		NauticalTwilight:     crossing(elevationNautical),
+		AstronomicalTwilight: crossing(elevationAstronomical),
+	}
+}
+
+// julianCentury returns the Julian century of Julian day jd.
+func julianCentury(jd float64) float64 {
+	return (jd - 2451545) / 36525
+}
+
+// sunGeometry returns the sun's geometric mean longitude and mean anomaly, in
+// degrees, and the eccentricity of the Earth's orbit at Julian century tc.
+func sunGeometry(tc float64) (float64, float64, float64) {
+	meanLongitude := math.Mod(280.46646+tc*(36000.76983+tc*0.0003032), 360)
+	meanAnomaly := 357.52911 + tc*(35999.05029-0.0001537*tc)
+	eccentricity := 0.016708634 - tc*(0.000042037+0.0000001267*tc)
+	return meanLongitude, meanAnomaly, eccentricity
+}
+
+// obliquity returns the corrected obliquity of the ecliptic, in degrees, at
+// Julian century tc.
+func obliquity(tc float64) float64 {
+	seconds := 21.448 - tc*(46.815+tc*(0.00059-tc*0.001813))
+	mean := 23 + (26+seconds/60)/60
+	return mean + 0.00256*math.Cos(radians(125.04-1934.136*tc))
+}
+
+// declination returns the sun's declination, in degrees, at Julian century
+// tc.
+func declination(tc float64) float64 {
+	meanLongitude, meanAnomaly, _ := sunGeometry(tc)
+	m := radians(meanAnomaly)
+	center := math.Sin(m)*(1.914602-tc*(0.004817+0.000014*tc)) +
+		math.Sin(2*m)*(0.019993-0.000101*tc) +
+		math.Sin(3*m)*0.000289
+	apparentLongitude := meanLongitude + center - 0.00569 - 0.00478*math.Sin(radians(125.04-1934.136*tc))
+	return degrees(math.Asin(math.Sin(radians(obliquity(tc))) * math.Sin(radians(apparentLongitude))))
+}
+
+// equationOfTime returns the equation of time, in minutes, at Julian century
+// tc.
+func equationOfTime(tc float64) float64 {
+	meanLongitude, meanAnomaly, e := sunGeometry(tc)
+	l0 := radians(meanLongitude)
+	m := radians(meanAnomaly)
+	y := math.Tan(radians(obliquity(tc)) / 2)
+	y *= y
+	eqTime := y*math.Sin(2*l0) -
+		2*e*math.Sin(m) +
+		4*e*y*math.Sin(m)*math.Cos(2*l0) -
+		0.5*y*y*math.Sin(4*l0) -
+		1.25*e*e*math.Sin(2*m)
+	return 4 * degrees(eqTime)
+}
+
+// hourAngle returns the hour angle, in degrees, at which the sun is at
+// elevation at latitude with declination. If the sun does not reach elevation
+// then it returns false and a positive value if the sun is always above
+// elevation or a negative value if the sun is always below elevation.
+func hourAngle(latitude, declination, elevation float64) (float64, bool) {
+	phi := radians(latitude)
+	delta := radians(declination)
+	cosHA := (math.Cos(radians(90-elevation)) - math.Sin(phi)*math.Sin(delta)) / (math.Cos(phi) * math.Cos(delta))
+	switch {
+	case cosHA < -1:
+		return 1, false
+	case cosHA > 1:
+		return -1, false
+	default:
+		return degrees(math.Acos(cosHA)), true
+	}
+}
+
+func degrees(x float64) float64 {
+	return x * 180 / math.Pi
+}
+
+func radians(x float64) float64 {
+	return x * math.Pi / 180
+}
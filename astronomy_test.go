@@ -0,0 +1,108 @@
+package darksky_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/twpayne/go-darksky"
+	"github.com/twpayne/go-darksky/dstest"
+)
+
+func TestDailyDataMoonPhase(t *testing.T) {
+	for _, tc := range []struct {
+		moonPhase            float64
+		expectedName         darksky.MoonPhaseName
+		expectedIllumination float64
+	}{
+		{moonPhase: 0, expectedName: darksky.MoonPhaseNew, expectedIllumination: 0},
+		{moonPhase: 0.05, expectedName: darksky.MoonPhaseNew, expectedIllumination: 0.024},
+		{moonPhase: 0.1, expectedName: darksky.MoonPhaseWaxingCrescent, expectedIllumination: 0.095},
+		{moonPhase: 0.25, expectedName: darksky.MoonPhaseFirstQuarter, expectedIllumination: 0.5},
+		{moonPhase: 0.4, expectedName: darksky.MoonPhaseWaxingGibbous, expectedIllumination: 0.905},
+		{moonPhase: 0.5, expectedName: darksky.MoonPhaseFull, expectedIllumination: 1},
+		{moonPhase: 0.6, expectedName: darksky.MoonPhaseWaningGibbous, expectedIllumination: 0.905},
+		{moonPhase: 0.75, expectedName: darksky.MoonPhaseLastQuarter, expectedIllumination: 0.5},
+		{moonPhase: 0.87, expectedName: darksky.MoonPhaseWaningCrescent, expectedIllumination: 0.158},
+		{moonPhase: 0.99, expectedName: darksky.MoonPhaseNew, expectedIllumination: 0.001},
+	} {
+		d := &darksky.DailyData{MoonPhase: tc.moonPhase}
+		assert.Equal(t, tc.expectedName, d.MoonPhaseName())
+		assert.InDelta(t, tc.expectedIllumination, d.MoonIllumination(), 0.001)
+	}
+}
+
+func TestForecastSolarEvents(t *testing.T) {
+	s := dstest.NewServer(
+		dstest.WithDefaultForecasts(),
+	)
+	defer s.Close()
+	c, err := s.NewClient()
+	require.NoError(t, err)
+	f, err := c.Forecast(context.Background(), 34.0219, -118.4814, &darksky.Time{Time: time.Unix(1556668800, 0)}, nil)
+	require.NoError(t, err)
+	require.NotEmpty(t, f.Daily.Data)
+	for _, d := range f.Daily.Data {
+		solarEvents := f.SolarEvents(d.Time.Time)
+		require.NotNil(t, solarEvents.Sun.Rise)
+		require.NotNil(t, solarEvents.Sun.Set)
+		assert.InDelta(t, d.SunriseTime.Unix(), solarEvents.Sun.Rise.Unix(), 120)
+		assert.InDelta(t, d.SunsetTime.Unix(), solarEvents.Sun.Set.Unix(), 120)
+		assert.Equal(t, f.Location(), solarEvents.Sun.Rise.Location())
+	}
+}
+
+func TestSolarEventsAt(t *testing.T) {
+	utc := func(s string) *darksky.Time {
+		tt, err := time.Parse(time.RFC3339, s)
+		require.NoError(t, err)
+		return &darksky.Time{Time: tt}
+	}
+
+	// Reference values are rounded to the nearest minute.
+	greenwich := darksky.SolarEventsAt(51.4769, 0, time.Date(2019, time.June, 21, 12, 0, 0, 0, time.UTC))
+	assert.InDelta(t, utc("2019-06-21T12:01:48Z").Unix(), greenwich.SolarNoon.Unix(), 60)
+	assert.InDelta(t, utc("2019-06-21T03:43:00Z").Unix(), greenwich.Sun.Rise.Unix(), 60)
+	assert.InDelta(t, utc("2019-06-21T20:21:00Z").Unix(), greenwich.Sun.Set.Unix(), 60)
+	assert.InDelta(t, utc("2019-06-21T02:55:00Z").Unix(), greenwich.CivilTwilight.Rise.Unix(), 60)
+	assert.InDelta(t, utc("2019-06-21T21:08:00Z").Unix(), greenwich.CivilTwilight.Set.Unix(), 60)
+	assert.NotNil(t, greenwich.NauticalTwilight.Rise)
+	assert.Equal(t, darksky.SolarCrossing{AlwaysAbove: true}, greenwich.AstronomicalTwilight)
+
+	tromsøSummer := darksky.SolarEventsAt(69.6496, 18.956, time.Date(2019, time.June, 21, 12, 0, 0, 0, time.UTC))
+	assert.Equal(t, darksky.SolarCrossing{AlwaysAbove: true}, tromsøSummer.Sun)
+	assert.Equal(t, darksky.SolarCrossing{AlwaysAbove: true}, tromsøSummer.CivilTwilight)
+	assert.NotNil(t, tromsøSummer.SolarNoon)
+
+	tromsøWinter := darksky.SolarEventsAt(69.6496, 18.956, time.Date(2019, time.December, 21, 12, 0, 0, 0, time.UTC))
+	assert.Equal(t, darksky.SolarCrossing{AlwaysBelow: true}, tromsøWinter.Sun)
+	require.NotNil(t, tromsøWinter.CivilTwilight.Rise)
+	require.NotNil(t, tromsøWinter.CivilTwilight.Set)
+	assert.True(t, tromsøWinter.CivilTwilight.Rise.Before(tromsøWinter.SolarNoon.Time))
+	assert.True(t, tromsøWinter.SolarNoon.Before(tromsøWinter.CivilTwilight.Set.Time))
+
+	tokyo, err := time.LoadLocation("Asia/Tokyo")
+	require.NoError(t, err)
+	tokyoEvents := darksky.SolarEventsAt(35.6762, 139.6503, time.Date(2019, time.May, 1, 0, 0, 0, 0, tokyo))
+	for _, tt := range []*darksky.Time{
+		tokyoEvents.AstronomicalTwilight.Rise,
+		tokyoEvents.NauticalTwilight.Rise,
+		tokyoEvents.CivilTwilight.Rise,
+		tokyoEvents.Sun.Rise,
+		tokyoEvents.SolarNoon,
+		tokyoEvents.Sun.Set,
+		tokyoEvents.CivilTwilight.Set,
+		tokyoEvents.NauticalTwilight.Set,
+		tokyoEvents.AstronomicalTwilight.Set,
+	} {
+		require.NotNil(t, tt)
+		year, month, day := tt.Date()
+		assert.Equal(t, []int{2019, 5, 1}, []int{year, int(month), day})
+		assert.Equal(t, tokyo, tt.Location())
+	}
+	assert.True(t, tokyoEvents.Sun.Rise.Before(tokyoEvents.SolarNoon.Time))
+	assert.True(t, tokyoEvents.CivilTwilight.Rise.Before(tokyoEvents.Sun.Rise.Time))
+}
@@ -0,0 +1,93 @@
+package darksky
+
+import (
+	"context"
+	"sync"
+)
+
+// DefaultConcurrency is the default maximum number of concurrent requests
+// sent by Forecasts.
+const DefaultConcurrency = 4
+
+// A Location is a location.
+type Location struct {
+	Latitude  float64
+	Longitude float64
+}
+
+// A ForecastResult is the result of a forecast request for a Location.
+type ForecastResult struct {
+	Location Location
+	Forecast *Forecast
+	Err      error
+}
+
+// WithConcurrency sets the maximum number of concurrent requests sent by
+// Forecasts and History.
+func WithConcurrency(n int) ClientOption {
+	return func(c *Client) {
+		c.concurrency = n
+	}
+}
+
+// Forecasts returns the forecasts for locations, sending at most the client's
+// concurrency requests at once. The results are in the same order as
+// locations. If ctx is done before a location's request is sent then the
+// location's result's Err is ctx.Err(). Requests are subject to the client's
+// rate limit and daily quota.
+func (c *Client) Forecasts(ctx context.Context, locations []Location, options *ForecastOptions) []*ForecastResult {
+	results := make([]*ForecastResult, len(locations))
+	for i, location := range locations {
+		results[i] = &ForecastResult{
+			Location: location,
+		}
+	}
+
+	sent := c.parallel(ctx, len(locations), func(i int) {
+		result := results[i]
+		result.Forecast, result.Err = c.Forecast(ctx, result.Location.Latitude, result.Location.Longitude, nil, options)
+	})
+	for _, result := range results[sent:] {
+		result.Err = ctx.Err()
+	}
+	return results
+}
+
+// parallel calls f for each integer in [0, n), with at most the client's
+// concurrency calls at once, until ctx is done. It returns the number of calls
+// made, which are always for the integers [0, sent).
+func (c *Client) parallel(ctx context.Context, n int, f func(int)) int {
+	workers := c.concurrency
+	if workers < 1 {
+		workers = 1
+	}
+	if workers > n {
+		workers = n
+	}
+
+	indexes := make(chan int)
+	var wg sync.WaitGroup
+	wg.Add(workers)
+	for i := 0; i < workers; i++ {
+		go func() {
+			defer wg.Done()
+			for index := range indexes {
+				f(index)
+			}
+		}()
+	}
+
+	sent := 0
+FOR:
+	for sent < n {
+		select {
+		case indexes <- sent:
+			sent++
+		case <-ctx.Done():
+			break FOR
+		}
+	}
+	close(indexes)
+	wg.Wait()
+	return sent
+}
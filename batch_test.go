@@ -0,0 +1,96 @@
+package darksky_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/twpayne/go-darksky"
+	"github.com/twpayne/go-darksky/dstest"
+)
+
+func TestClientForecasts(t *testing.T) {
+	s := dstest.NewServer(
+		dstest.WithDefaultForecasts(),
+	)
+	defer s.Close()
+	c, err := s.NewClient()
+	require.NoError(t, err)
+
+	santaMonica := darksky.Location{Latitude: 34.0219, Longitude: -118.4814}
+	nullIsland := darksky.Location{Latitude: 0, Longitude: 0}
+	locations := []darksky.Location{santaMonica, nullIsland, santaMonica}
+	results := c.Forecasts(context.Background(), locations, &darksky.ForecastOptions{
+		Lang: darksky.LangFR,
+	})
+	require.Len(t, results, len(locations))
+	for i, result := range results {
+		assert.Equal(t, locations[i], result.Location)
+	}
+	require.NoError(t, results[0].Err)
+	assert.Equal(t, "Ciel Dégagé", results[0].Forecast.Currently.Summary)
+	assert.True(t, errors.Is(results[1].Err, darksky.ErrNotFound))
+	require.NoError(t, results[2].Err)
+	assert.Equal(t, "Ciel Dégagé", results[2].Forecast.Currently.Summary)
+}
+
+func TestClientForecastsConcurrency(t *testing.T) {
+	var inFlight, maxInFlight int32
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := atomic.AddInt32(&inFlight, 1)
+		for {
+			max := atomic.LoadInt32(&maxInFlight)
+			if n <= max || atomic.CompareAndSwapInt32(&maxInFlight, max, n) {
+				break
+			}
+		}
+		time.Sleep(5 * time.Millisecond)
+		atomic.AddInt32(&inFlight, -1)
+		_, _ = w.Write([]byte(`{}`))
+	}))
+	defer s.Close()
+	c, err := darksky.NewClient(
+		darksky.WithBaseURL(s.URL),
+		darksky.WithHTTPClient(s.Client()),
+		darksky.WithConcurrency(3),
+	)
+	require.NoError(t, err)
+
+	locations := make([]darksky.Location, 12)
+	for i := range locations {
+		locations[i] = darksky.Location{Latitude: float64(i), Longitude: float64(i)}
+	}
+	for _, result := range c.Forecasts(context.Background(), locations, nil) {
+		assert.NoError(t, result.Err)
+	}
+	assert.Equal(t, int32(3), atomic.LoadInt32(&maxInFlight))
+}
+
+func TestClientForecastsCanceled(t *testing.T) {
+	s := dstest.NewServer(
+		dstest.WithDefaultForecasts(),
+	)
+	defer s.Close()
+	c, err := s.NewClient()
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	results := c.Forecasts(ctx, []darksky.Location{
+		{Latitude: 34.0219, Longitude: -118.4814},
+		{Latitude: 34.0219, Longitude: -118.4814},
+	}, nil)
+	require.Len(t, results, 2)
+	for _, result := range results {
+		assert.Nil(t, result.Forecast)
+		assert.Error(t, result.Err)
+	}
+	assert.Equal(t, 0, s.ForecastAPICalls())
+}
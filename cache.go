@@ -0,0 +1,226 @@
+package darksky
+
+import (
+	"bytes"
+	"container/list"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// historicalAge is the age after which a time machine response is considered
+// historical and therefore will not change.
+const historicalAge = 48 * time.Hour
+
+// A Cache caches response bodies. Implementations must be safe for concurrent
+// use. Callers must not modify values passed to Set or returned by Get.
+type Cache interface {
+	// Get returns the value associated with key and whether it was found.
+	Get(key string) ([]byte, bool)
+	// Set associates value with key. If ttl is zero then the value does not
+	// expire.
+	Set(key string, value []byte, ttl time.Duration)
+}
+
+// A MemoryCache is an in-memory Cache that evicts the least recently used
+// entries.
+type MemoryCache struct {
+	mutex      sync.Mutex
+	maxEntries int
+	lru        *list.List
+	elements   map[string]*list.Element
+	now        func() time.Time
+}
+
+type memoryCacheEntry struct {
+	key     string
+	value   []byte
+	expires time.Time
+}
+
+// A FileCache is a Cache that stores values in files in a directory.
+type FileCache struct {
+	dir string
+	now func() time.Time
+}
+
+// WithCache sets the cache. Responses to forecast requests, and to time
+// machine requests for recent times, expire after ttl. If ttl is zero or
+// negative then these responses are not cached. Responses to time machine
+// requests for times more than two days in the past never expire, whatever
+// ttl is.
+func WithCache(cache Cache, ttl time.Duration) ClientOption {
+	return func(c *Client) {
+		c.cache = cache
+		c.ttl = ttl
+	}
+}
+
+// NewMemoryCache returns a new MemoryCache with at most maxEntries entries. If
+// maxEntries is zero then there is no limit.
+func NewMemoryCache(maxEntries int) *MemoryCache {
+	return &MemoryCache{
+		maxEntries: maxEntries,
+		lru:        list.New(),
+		elements:   make(map[string]*list.Element),
+		now:        time.Now,
+	}
+}
+
+// Get implements Cache.Get.
+func (c *MemoryCache) Get(key string) ([]byte, bool) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	element, ok := c.elements[key]
+	if !ok {
+		return nil, false
+	}
+	entry := element.Value.(*memoryCacheEntry)
+	if !entry.expires.IsZero() && !c.now().Before(entry.expires) {
+		c.lru.Remove(element)
+		delete(c.elements, key)
+		return nil, false
+	}
+	c.lru.MoveToFront(element)
+	return entry.value, true
+}
+
+// Len returns the number of entries in c, including expired entries that have
+// not yet been evicted.
+func (c *MemoryCache) Len() int {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	return c.lru.Len()
+}
+
+// Set implements Cache.Set.
+func (c *MemoryCache) Set(key string, value []byte, ttl time.Duration) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	entry := &memoryCacheEntry{
+		key:   key,
+		value: value,
+	}
+	if ttl != 0 {
+		entry.expires = c.now().Add(ttl)
+	}
+	if element, ok := c.elements[key]; ok {
+		element.Value = entry
+		c.lru.MoveToFront(element)
+		return
+	}
+	c.elements[key] = c.lru.PushFront(entry)
+	if c.maxEntries != 0 && c.lru.Len() > c.maxEntries {
+		element := c.lru.Back()
+		c.lru.Remove(element)
+		delete(c.elements, element.Value.(*memoryCacheEntry).key)
+	}
+}
+
+// NewFileCache returns a new FileCache that stores values in dir, which must
+// already exist.
+func NewFileCache(dir string) *FileCache {
+	return &FileCache{
+		dir: dir,
+		now: time.Now,
+	}
+}
+
+// Get implements Cache.Get. Errors are treated as cache misses.
+func (c *FileCache) Get(key string) ([]byte, bool) {
+	filename := c.filename(key)
+	data, err := ioutil.ReadFile(filename)
+	if err != nil {
+		return nil, false
+	}
+	i := bytes.IndexByte(data, '\n')
+	if i == -1 {
+		return nil, false
+	}
+	expires, err := strconv.ParseInt(string(data[:i]), 10, 64)
+	if err != nil {
+		return nil, false
+	}
+	if expires != 0 && c.now().Unix() >= expires {
+		_ = os.Remove(filename)
+		return nil, false
+	}
+	return data[i+1:], true
+}
+
+// Set implements Cache.Set. Errors are ignored.
+func (c *FileCache) Set(key string, value []byte, ttl time.Duration) {
+	var expires int64
+	if ttl != 0 {
+		expires = c.now().Add(ttl).Unix()
+	}
+	f, err := ioutil.TempFile(c.dir, ".tmp-")
+	if err != nil {
+		return
+	}
+	defer os.Remove(f.Name())
+	_, err = fmt.Fprintf(f, "%d\n", expires)
+	if err == nil {
+		_, err = f.Write(value)
+	}
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return
+	}
+	_ = os.Rename(f.Name(), c.filename(key))
+}
+
+func (c *FileCache) filename(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return filepath.Join(c.dir, hex.EncodeToString(sum[:]))
+}
+
+// cacheTTL returns the time to live of a response to a request for time t, and
+// whether the response should be cached. Historical responses have a time to
+// live of zero, meaning that they never expire.
+func (c *Client) cacheTTL(t *Time) (time.Duration, bool) {
+	if t != nil && !t.IsZero() && c.now().Sub(t.Time) > historicalAge {
+		return 0, true
+	}
+	return c.ttl, c.ttl > 0
+}
+
+// forecastCacheKey returns a normalized key for a request. Requests that differ
+// only in the order of excluded blocks or in whether defaults are explicit
+// have the same key. Forecast requests have an empty time, so they never have
+// the same key as time machine requests.
+func forecastCacheKey(latitude, longitude float64, t *Time, options *ForecastOptions) string {
+	var sec string
+	if t != nil && !t.IsZero() {
+		sec = strconv.FormatInt(t.Unix(), 10)
+	}
+	exclude, extend, lang, units := "", DefaultExtend, DefaultLang, DefaultUnits
+	if options != nil {
+		blockStrs := make([]string, len(options.Exclude))
+		for i, block := range options.Exclude {
+			blockStrs[i] = string(block)
+		}
+		sort.Strings(blockStrs)
+		exclude = strings.Join(blockStrs, ",")
+		if options.Extend != "" {
+			extend = options.Extend
+		}
+		if options.Lang != "" {
+			lang = options.Lang
+		}
+		if options.Units != "" {
+			units = options.Units
+		}
+	}
+	return fmt.Sprintf("%f,%f,%s?exclude=%s&extend=%s&lang=%s&units=%s", latitude, longitude, sec, exclude, extend, lang, units)
+}
@@ -0,0 +1,170 @@
+package darksky
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMemoryCache(t *testing.T) {
+	now := time.Date(2019, 5, 1, 0, 0, 0, 0, time.UTC)
+	c := NewMemoryCache(2)
+	c.now = func() time.Time { return now }
+
+	c.Set("a", []byte("1"), time.Minute)
+	c.Set("b", []byte("2"), 0)
+	value, ok := c.Get("a")
+	assert.True(t, ok)
+	assert.Equal(t, []byte("1"), value)
+
+	c.Set("c", []byte("3"), 0)
+	assert.Equal(t, 2, c.Len())
+	_, ok = c.Get("b")
+	assert.False(t, ok, "least recently used entry should be evicted")
+
+	now = now.Add(time.Minute)
+	_, ok = c.Get("a")
+	assert.False(t, ok, "expired entry should not be returned")
+	value, ok = c.Get("c")
+	assert.True(t, ok)
+	assert.Equal(t, []byte("3"), value)
+}
+
+func TestFileCache(t *testing.T) {
+	dir, err := ioutil.TempDir("", "go-darksky-test")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	now := time.Date(2019, 5, 1, 0, 0, 0, 0, time.UTC)
+	c := NewFileCache(dir)
+	c.now = func() time.Time { return now }
+
+	_, ok := c.Get("a")
+	assert.False(t, ok)
+
+	c.Set("a", []byte("1"), time.Minute)
+	c.Set("b", []byte("2\n2"), 0)
+	value, ok := c.Get("a")
+	assert.True(t, ok)
+	assert.Equal(t, []byte("1"), value)
+
+	now = now.Add(time.Minute)
+	_, ok = c.Get("a")
+	assert.False(t, ok)
+	value, ok = c.Get("b")
+	assert.True(t, ok)
+	assert.Equal(t, []byte("2\n2"), value)
+}
+
+func TestForecastCacheKey(t *testing.T) {
+	tm := &Time{Time: time.Unix(1556668800, 0)}
+	assert.Equal(t,
+		forecastCacheKey(34.0219, -118.4814, nil, nil),
+		forecastCacheKey(34.0219, -118.4814, &Time{}, &ForecastOptions{Lang: LangEN, Units: UnitsUS}),
+	)
+	assert.Equal(t,
+		forecastCacheKey(34.0219, -118.4814, tm, &ForecastOptions{Exclude: []Block{BlockFlags, BlockAlerts}}),
+		forecastCacheKey(34.0219, -118.4814, tm, &ForecastOptions{Exclude: []Block{BlockAlerts, BlockFlags}}),
+	)
+	assert.NotEqual(t,
+		forecastCacheKey(34.0219, -118.4814, nil, nil),
+		forecastCacheKey(34.0219, -118.4814, tm, nil),
+	)
+	assert.NotEqual(t,
+		forecastCacheKey(34.0219, -118.4814, nil, nil),
+		forecastCacheKey(34.0219, -118.4814, &Time{Time: time.Unix(0, 0)}, nil),
+	)
+	assert.NotEqual(t,
+		forecastCacheKey(34.0219, -118.4814, nil, nil),
+		forecastCacheKey(34.0219, -118.4814, nil, &ForecastOptions{Units: UnitsSI}),
+	)
+}
+
+func TestClientCache(t *testing.T) {
+	var requests int32
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		_, _ = w.Write([]byte(`{"latitude":42.3601,"longitude":-71.0589,"timezone":"America/New_York"}`))
+	}))
+	defer s.Close()
+	cache := NewMemoryCache(0)
+	c, err := NewClient(
+		WithBaseURL(s.URL),
+		WithHTTPClient(s.Client()),
+		WithKey("key"),
+		WithCache(cache, time.Hour),
+	)
+	require.NoError(t, err)
+
+	past := &Time{Time: time.Date(2019, 1, 1, 0, 0, 0, 0, time.UTC)}
+	for i := 0; i < 2; i++ {
+		forecast, err := c.Forecast(context.Background(), 42.3601, -71.0589, nil, nil)
+		require.NoError(t, err)
+		assert.Equal(t, "America/New_York", forecast.Timezone)
+		_, err = c.Forecast(context.Background(), 42.3601, -71.0589, past, nil)
+		require.NoError(t, err)
+	}
+	assert.Equal(t, int32(2), atomic.LoadInt32(&requests))
+	assert.Equal(t, 2, cache.Len())
+
+	for _, tc := range []struct {
+		t           *Time
+		expectedTTL time.Duration
+	}{
+		{t: nil, expectedTTL: time.Hour},
+		{t: past, expectedTTL: 0},
+		{t: &Time{Time: time.Now().Add(-time.Hour)}, expectedTTL: time.Hour},
+	} {
+		ttl, ok := c.cacheTTL(tc.t)
+		assert.True(t, ok)
+		assert.Equal(t, tc.expectedTTL, ttl)
+	}
+}
+
+func TestClientCacheZeroTTL(t *testing.T) {
+	var requests int32
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		_, _ = w.Write([]byte(`{"latitude":42.3601,"longitude":-71.0589,"timezone":"America/New_York"}`))
+	}))
+	defer s.Close()
+
+	past := &Time{Time: time.Date(2019, 1, 1, 0, 0, 0, 0, time.UTC)}
+	recent := &Time{Time: time.Now().Add(-time.Hour)}
+	for _, ttl := range []time.Duration{0, -time.Hour} {
+		atomic.StoreInt32(&requests, 0)
+		cache := NewMemoryCache(0)
+		c, err := NewClient(
+			WithBaseURL(s.URL),
+			WithHTTPClient(s.Client()),
+			WithKey("key"),
+			WithCache(cache, ttl),
+		)
+		require.NoError(t, err)
+
+		for i := 0; i < 2; i++ {
+			for _, tm := range []*Time{nil, recent, past} {
+				_, err := c.Forecast(context.Background(), 42.3601, -71.0589, tm, nil)
+				require.NoError(t, err)
+			}
+		}
+		assert.Equal(t, int32(5), atomic.LoadInt32(&requests), ttl)
+		assert.Equal(t, 1, cache.Len(), ttl)
+
+		for _, tm := range []*Time{nil, recent} {
+			_, ok := c.cacheTTL(tm)
+			assert.False(t, ok, ttl)
+		}
+		historicalTTL, ok := c.cacheTTL(past)
+		assert.True(t, ok)
+		assert.Equal(t, time.Duration(0), historicalTTL)
+	}
+}
@@ -3,8 +3,10 @@
 package darksky
 
 import (
+	"errors"
 	"fmt"
 	"net/http"
+	"strings"
 	"time"
 
 	"golang.org/x/text/language"
@@ -28,9 +30,23 @@ const (
 
 	// DefaultUnits are the default units.
 	DefaultUnits = UnitsUS
+
+	// RedactedKey replaces the key in URLs in errors and response metadata.
+	RedactedKey = "REDACTED"
+)
+
+// Errors matched by an *Error with errors.Is, according to its response's
+// status code.
+var (
+	ErrBadRequest   = errors.New("darksky: bad request")
+	ErrUnauthorized = errors.New("darksky: unauthorized")
+	ErrNotFound     = errors.New("darksky: not found")
+	ErrRateLimited  = errors.New("darksky: rate limited")
+	ErrServerError  = errors.New("darksky: server error")
 )
 
-// An Error is an error.
+// An Error is an error. Request contains the key, so Request.URL should not be
+// logged. Use URL instead.
 type Error struct {
 	Request      *http.Request
 	Response     *http.Response
@@ -41,14 +57,19 @@ type Error struct {
 	}
 }
 
-// ResponseMetadata are extra metadata associated with a response.
+// ResponseMetadata are extra metadata associated with a response. URL is the
+// URL of the request with the key redacted.
 type ResponseMetadata struct {
+	URL              string
 	StatusCode       int
 	ForecastAPICalls int
 	ResponseTime     time.Duration
+	Attempt          int
 }
 
-// A ResponseMetadataCallback is function that receives a ResponseMetadata.
+// A ResponseMetadataCallback is function that receives a ResponseMetadata. It
+// is called once for every response received, including responses to attempts
+// that are subsequently retried.
 type ResponseMetadataCallback func(*ResponseMetadata)
 
 // A Client is a Dark Sky Client.
@@ -58,6 +79,16 @@ type Client struct {
 	key                      string
 	langs                    []Lang
 	responseMetadataCallback ResponseMetadataCallback
+	retryPolicy              *RetryPolicy
+	cache                    Cache
+	ttl                      time.Duration
+	flights                  flightGroup
+	quota                    *quota
+	rateLimiter              *rateLimiter
+	concurrency              int
+	optionalFields           bool
+	strictDecoding           bool
+	now                      func() time.Time
 	matcher                  language.Matcher
 }
 
@@ -103,13 +134,25 @@ func WithResponseMetadataCallback(rmc ResponseMetadataCallback) ClientOption {
 // NewClient returns a new Client.
 func NewClient(options ...ClientOption) (*Client, error) {
 	c := &Client{
-		httpClient: http.DefaultClient,
-		baseURL:    DefaultBaseURL,
-		langs:      append([]Lang{DefaultLang}, Langs...),
+		httpClient:  http.DefaultClient,
+		baseURL:     DefaultBaseURL,
+		langs:       append([]Lang{DefaultLang}, Langs...),
+		concurrency: DefaultConcurrency,
+		now:         time.Now,
 	}
 	for _, o := range options {
 		o(c)
 	}
+	if c.rateLimiter != nil {
+		if err := c.rateLimiter.validate(); err != nil {
+			return nil, err
+		}
+	}
+	if c.retryPolicy != nil {
+		if err := c.retryPolicy.validate(); err != nil {
+			return nil, err
+		}
+	}
 	tags := make([]language.Tag, len(c.langs))
 	for i, lang := range c.langs {
 		tag, err := language.Parse(string(lang))
@@ -135,9 +178,56 @@ func (e *Error) Error() string {
 	if e.Details.ErrorStr != "" {
 		return e.Details.ErrorStr
 	}
-	s := fmt.Sprintf("%s: %d %s", e.Request.URL, e.Response.StatusCode, http.StatusText(e.Response.StatusCode))
+	s := fmt.Sprintf("%s: %d %s", e.URL(), e.Response.StatusCode, http.StatusText(e.Response.StatusCode))
 	if len(e.ResponseBody) != 0 {
 		s += ": " + string(e.ResponseBody)
 	}
 	return s
 }
+
+// Is returns whether e matches target. It allows errors.Is to match e against
+// ErrBadRequest, ErrUnauthorized, ErrNotFound, ErrRateLimited, and
+// ErrServerError.
+func (e *Error) Is(target error) bool {
+	statusCode := e.Response.StatusCode
+	switch target {
+	case ErrBadRequest:
+		return statusCode == http.StatusBadRequest
+	case ErrUnauthorized:
+		return statusCode == http.StatusUnauthorized || statusCode == http.StatusForbidden
+	case ErrNotFound:
+		return statusCode == http.StatusNotFound
+	case ErrRateLimited:
+		return statusCode == http.StatusTooManyRequests
+	case ErrServerError:
+		return statusCode >= http.StatusInternalServerError
+	default:
+		return false
+	}
+}
+
+// IsTemporary returns whether e is transient, i.e. whether the request might
+// succeed if retried.
+func (e *Error) IsTemporary() bool {
+	return e.Is(ErrRateLimited) || e.Is(ErrServerError)
+}
+
+// URL returns the URL of the request with the key redacted.
+func (e *Error) URL() string {
+	return redactURL(e.Request.URL.String())
+}
+
+// redactURL returns urlStr with the key replaced by RedactedKey.
+func redactURL(urlStr string) string {
+	const forecastPathPrefix = "/forecast/"
+	i := strings.Index(urlStr, forecastPathPrefix)
+	if i == -1 {
+		return urlStr
+	}
+	keyStart := i + len(forecastPathPrefix)
+	keyLen := strings.IndexByte(urlStr[keyStart:], '/')
+	if keyLen <= 0 {
+		return urlStr
+	}
+	return urlStr[:keyStart] + RedactedKey + urlStr[keyStart+keyLen:]
+}
@@ -1,6 +1,12 @@
 package darksky
 
 import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -76,3 +82,138 @@ func TestClientMatchLang(t *testing.T) {
 		})
 	}
 }
+
+func TestErrorRedactsKey(t *testing.T) {
+	const key = "0123456789abcdef"
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer s.Close()
+
+	var urls []string
+	c, err := NewClient(
+		WithBaseURL(s.URL),
+		WithHTTPClient(s.Client()),
+		WithKey(key),
+		WithResponseMetadataCallback(func(rm *ResponseMetadata) {
+			urls = append(urls, rm.URL)
+		}),
+	)
+	require.NoError(t, err)
+	_, err = c.Forecast(context.Background(), 42.3601, -71.0589, nil, &ForecastOptions{Units: UnitsSI})
+	require.Error(t, err)
+	e, ok := err.(*Error)
+	require.True(t, ok)
+	expectedURL := s.URL + "/forecast/" + RedactedKey + "/42.360100,-71.058900?units=si"
+	assert.Equal(t, expectedURL, e.URL())
+	assert.Equal(t, []string{expectedURL}, urls)
+	for _, str := range []string{
+		e.Error(),
+		fmt.Sprintf("%v", err),
+		fmt.Sprintf("%s", err),
+		e.URL(),
+		urls[0],
+	} {
+		assert.NotContains(t, str, key)
+	}
+
+	c, err = NewClient(
+		WithBaseURL("http://0.0.0.0"),
+		WithKey(key),
+	)
+	require.NoError(t, err)
+	_, err = c.Forecast(context.Background(), 42.3601, -71.0589, nil, nil)
+	require.Error(t, err)
+	assert.NotContains(t, err.Error(), key)
+	assert.Contains(t, err.Error(), RedactedKey)
+}
+
+func TestRedactURL(t *testing.T) {
+	for _, tc := range []struct {
+		urlStr   string
+		expected string
+	}{
+		{
+			urlStr:   "https://api.darksky.net/forecast/key/42.360100,-71.058900",
+			expected: "https://api.darksky.net/forecast/REDACTED/42.360100,-71.058900",
+		},
+		{
+			urlStr:   "/forecast/key/42.360100,-71.058900,1546300800?lang=fr",
+			expected: "/forecast/REDACTED/42.360100,-71.058900,1546300800?lang=fr",
+		},
+		{
+			urlStr:   "/forecast//42.360100,-71.058900",
+			expected: "/forecast//42.360100,-71.058900",
+		},
+		{
+			urlStr:   "https://example.com/",
+			expected: "https://example.com/",
+		},
+	} {
+		assert.Equal(t, tc.expected, redactURL(tc.urlStr))
+	}
+}
+
+func TestErrorIs(t *testing.T) {
+	sentinels := []error{
+		ErrBadRequest,
+		ErrUnauthorized,
+		ErrNotFound,
+		ErrRateLimited,
+		ErrServerError,
+	}
+	for _, tc := range []struct {
+		statusCode        int
+		expectedErr       error
+		expectedTemporary bool
+	}{
+		{
+			statusCode:  http.StatusBadRequest,
+			expectedErr: ErrBadRequest,
+		},
+		{
+			statusCode:  http.StatusUnauthorized,
+			expectedErr: ErrUnauthorized,
+		},
+		{
+			statusCode:  http.StatusForbidden,
+			expectedErr: ErrUnauthorized,
+		},
+		{
+			statusCode:  http.StatusNotFound,
+			expectedErr: ErrNotFound,
+		},
+		{
+			statusCode:        http.StatusTooManyRequests,
+			expectedErr:       ErrRateLimited,
+			expectedTemporary: true,
+		},
+		{
+			statusCode:        http.StatusInternalServerError,
+			expectedErr:       ErrServerError,
+			expectedTemporary: true,
+		},
+		{
+			statusCode:        http.StatusServiceUnavailable,
+			expectedErr:       ErrServerError,
+			expectedTemporary: true,
+		},
+		{
+			statusCode: http.StatusTeapot,
+		},
+	} {
+		t.Run(strconv.Itoa(tc.statusCode), func(t *testing.T) {
+			var err error = &Error{
+				Request:  httptest.NewRequest(http.MethodGet, "/forecast/key/0,0", nil),
+				Response: &http.Response{StatusCode: tc.statusCode},
+			}
+			err = fmt.Errorf("wrapped: %w", err)
+			for _, sentinel := range sentinels {
+				assert.Equal(t, sentinel == tc.expectedErr, errors.Is(err, sentinel), sentinel.Error())
+			}
+			var e *Error
+			require.True(t, errors.As(err, &e))
+			assert.Equal(t, tc.expectedTemporary, e.IsTemporary())
+		})
+	}
+}
@@ -0,0 +1,209 @@
+package darksky
+
+import (
+	"math"
+	"time"
+)
+
+// beaufortLimits are the lower limits of wind speed, in meters per second, for
+// Beaufort numbers 1 to 12.
+var beaufortLimits = []float64{0.5, 1.6, 3.4, 5.5, 8.0, 10.8, 13.9, 17.2, 20.8, 24.5, 28.5, 32.7}
+
+// cardinalDirections are the sixteen points of the compass.
+var cardinalDirections = []string{
+	"N", "NNE", "NE", "ENE", "E", "ESE", "SE", "SSE",
+	"S", "SSW", "SW", "WSW", "W", "WNW", "NW", "NNW",
+}
+
+// Beaufort returns the Beaufort number of c's wind speed, where c is in units.
+func (c *Currently) Beaufort(units Units) (int, error) {
+	return beaufort(c.WindSpeed, units)
+}
+
+// CardinalWindDirection returns the point of the sixteen-point compass nearest
+// to c's wind bearing, for example "NNE".
+func (c *Currently) CardinalWindDirection() string {
+	return cardinalDirection(c.WindBearing)
+}
+
+// HeatIndex returns the heat index of c, in the temperature units of units,
+// using the US National Weather Service's algorithm.
+func (c *Currently) HeatIndex(units Units) (float64, error) {
+	return heatIndex(c.Temperature, c.Humidity, units)
+}
+
+// Humidex returns the humidex of c, in the temperature units of units.
+func (c *Currently) Humidex(units Units) (float64, error) {
+	return humidex(c.Temperature, c.DewPoint, units)
+}
+
+// RelativeHumidity returns the relative humidity of c, between 0 and 1,
+// calculated from its temperature and dew point.
+func (c *Currently) RelativeHumidity(units Units) (float64, error) {
+	return relativeHumidity(c.Temperature, c.DewPoint, units)
+}
+
+// WindChill returns the wind chill of c, in the temperature units of units.
+// Wind chill is only defined for temperatures at or below 10°C and wind
+// speeds above 4.8km/h, otherwise the temperature is returned.
+func (c *Currently) WindChill(units Units) (float64, error) {
+	return windChill(c.Temperature, c.WindSpeed, units)
+}
+
+// Beaufort returns the Beaufort number of d's wind speed, where d is in units.
+func (d *DailyData) Beaufort(units Units) (int, error) {
+	return beaufort(d.WindSpeed, units)
+}
+
+// CardinalWindDirection returns the point of the sixteen-point compass nearest
+// to d's wind bearing, for example "NNE".
+func (d *DailyData) CardinalWindDirection() string {
+	return cardinalDirection(d.WindBearing)
+}
+
+// Daylight returns the time between d's sunrise and sunset, or zero if either
+// is missing, for example during polar day or night.
+func (d *DailyData) Daylight() time.Duration {
+	if d.SunriseTime == nil || d.SunsetTime == nil {
+		return 0
+	}
+	return d.SunsetTime.Sub(d.SunriseTime.Time)
+}
+
+// Beaufort returns the Beaufort number of d's wind speed, where d is in units.
+func (d *HourlyData) Beaufort(units Units) (int, error) {
+	return beaufort(d.WindSpeed, units)
+}
+
+// CardinalWindDirection returns the point of the sixteen-point compass nearest
+// to d's wind bearing, for example "NNE".
+func (d *HourlyData) CardinalWindDirection() string {
+	return cardinalDirection(d.WindBearing)
+}
+
+// HeatIndex returns the heat index of d, in the temperature units of units,
+// using the US National Weather Service's algorithm.
+func (d *HourlyData) HeatIndex(units Units) (float64, error) {
+	return heatIndex(d.Temperature, d.Humidity, units)
+}
+
+// Humidex returns the humidex of d, in the temperature units of units.
+func (d *HourlyData) Humidex(units Units) (float64, error) {
+	return humidex(d.Temperature, d.DewPoint, units)
+}
+
+// RelativeHumidity returns the relative humidity of d, between 0 and 1,
+// calculated from its temperature and dew point.
+func (d *HourlyData) RelativeHumidity(units Units) (float64, error) {
+	return relativeHumidity(d.Temperature, d.DewPoint, units)
+}
+
+// WindChill returns the wind chill of d, in the temperature units of units.
+// Wind chill is only defined for temperatures at or below 10°C and wind
+// speeds above 4.8km/h, otherwise the temperature is returned.
+func (d *HourlyData) WindChill(units Units) (float64, error) {
+	return windChill(d.Temperature, d.WindSpeed, units)
+}
+
+// siConverters returns converters from units to SI units and back.
+func siConverters(units Units) (*unitConverter, *unitConverter, error) {
+	toSI, err := newUnitConverter(units, UnitsSI)
+	if err != nil {
+		return nil, nil, err
+	}
+	fromSI, err := newUnitConverter(UnitsSI, units)
+	if err != nil {
+		return nil, nil, err
+	}
+	return toSI, fromSI, nil
+}
+
+func beaufort(windSpeed float64, units Units) (int, error) {
+	toSI, _, err := siConverters(units)
+	if err != nil {
+		return 0, err
+	}
+	v := toSI.speed(windSpeed)
+	for i, limit := range beaufortLimits {
+		if v < limit {
+			return i, nil
+		}
+	}
+	return len(beaufortLimits), nil
+}
+
+func cardinalDirection(bearing float64) string {
+	n := len(cardinalDirections)
+	i := int(math.Floor(bearing*float64(n)/360+0.5)) % n
+	if i < 0 {
+		i += n
+	}
+	return cardinalDirections[i]
+}
+
+// heatIndex returns the heat index, see
+// https://www.wpc.ncep.noaa.gov/html/heatindex_equation.shtml.
+func heatIndex(temperature, humidity float64, units Units) (float64, error) {
+	toSI, fromSI, err := siConverters(units)
+	if err != nil {
+		return 0, err
+	}
+	t := toSI.temperature(temperature)*9/5 + 32
+	rh := 100 * humidity
+	hi := 0.5 * (t + 61 + (t-68)*1.2 + rh*0.094)
+	if (hi+t)/2 >= 80 {
+		hi = -42.379 + 2.04901523*t + 10.14333127*rh -
+			0.22475541*t*rh - 0.00683783*t*t - 0.05481717*rh*rh +
+			0.00122874*t*t*rh + 0.00085282*t*rh*rh - 0.00000199*t*t*rh*rh
+		switch {
+		case rh < 13 && 80 <= t && t <= 112:
+			hi -= (13 - rh) / 4 * math.Sqrt((17-math.Abs(t-95))/17)
+		case rh > 85 && 80 <= t && t <= 87:
+			hi += (rh - 85) / 10 * (87 - t) / 5
+		}
+	}
+	return fromSI.temperature((hi - 32) * 5 / 9), nil
+}
+
+// humidex returns the humidex, see
+// https://en.wikipedia.org/wiki/Humidex.
+func humidex(temperature, dewPoint float64, units Units) (float64, error) {
+	toSI, fromSI, err := siConverters(units)
+	if err != nil {
+		return 0, err
+	}
+	t := toSI.temperature(temperature)
+	td := toSI.temperature(dewPoint)
+	e := 6.11 * math.Exp(5417.7530*(1/273.16-1/(273.15+td)))
+	return fromSI.temperature(t + 0.5555*(e-10)), nil
+}
+
+// relativeHumidity returns the relative humidity using the Magnus formula with
+// the coefficients from Alduchov and Eskridge (1996).
+func relativeHumidity(temperature, dewPoint float64, units Units) (float64, error) {
+	toSI, _, err := siConverters(units)
+	if err != nil {
+		return 0, err
+	}
+	t := toSI.temperature(temperature)
+	td := toSI.temperature(dewPoint)
+	const a, b = 17.625, 243.04
+	return math.Exp(a*td/(b+td) - a*t/(b+t)), nil
+}
+
+// windChill returns the wind chill using the formula of the US National
+// Weather Service and Environment Canada, see
+// https://en.wikipedia.org/wiki/Wind_chill.
+func windChill(temperature, windSpeed float64, units Units) (float64, error) {
+	toSI, fromSI, err := siConverters(units)
+	if err != nil {
+		return 0, err
+	}
+	t := toSI.temperature(temperature)
+	v := 3.6 * toSI.speed(windSpeed)
+	if t > 10 || v <= 4.8 {
+		return temperature, nil
+	}
+	v016 := math.Pow(v, 0.16)
+	return fromSI.temperature(13.12 + 0.6215*t - 11.37*v016 + 0.3965*t*v016), nil
+}
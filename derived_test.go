@@ -0,0 +1,304 @@
+package darksky_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/twpayne/go-darksky"
+)
+
+func TestWindChill(t *testing.T) {
+	for _, tc := range []struct {
+		name        string
+		units       darksky.Units
+		temperature float64
+		windSpeed   float64
+		expected    float64
+	}{
+		// Reference values are from the US National Weather Service's and
+		// Environment Canada's wind chill charts.
+		{
+			name:        "nws_0F_15mph",
+			units:       darksky.UnitsUS,
+			temperature: 0,
+			windSpeed:   15,
+			expected:    -19,
+		},
+		{
+			name:        "nws_minus20F_30mph",
+			units:       darksky.UnitsUS,
+			temperature: -20,
+			windSpeed:   30,
+			expected:    -53,
+		},
+		{
+			name:        "ec_minus20C_30kmh",
+			units:       darksky.UnitsCA,
+			temperature: -20,
+			windSpeed:   30,
+			expected:    -33,
+		},
+		{
+			name:        "si_minus10C_5ms",
+			units:       darksky.UnitsSI,
+			temperature: -10,
+			windSpeed:   5,
+			expected:    -17,
+		},
+		{
+			name:        "too_warm",
+			units:       darksky.UnitsSI,
+			temperature: 15,
+			windSpeed:   10,
+			expected:    15,
+		},
+		{
+			name:        "calm",
+			units:       darksky.UnitsUK2,
+			temperature: -10,
+			windSpeed:   1,
+			expected:    -10,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			c := &darksky.Currently{
+				Temperature: tc.temperature,
+				WindSpeed:   tc.windSpeed,
+			}
+			actual, err := c.WindChill(tc.units)
+			require.NoError(t, err)
+			assert.InDelta(t, tc.expected, actual, 0.5)
+
+			h := &darksky.HourlyData{
+				Temperature: tc.temperature,
+				WindSpeed:   tc.windSpeed,
+			}
+			actual, err = h.WindChill(tc.units)
+			require.NoError(t, err)
+			assert.InDelta(t, tc.expected, actual, 0.5)
+		})
+	}
+}
+
+func TestHeatIndex(t *testing.T) {
+	for _, tc := range []struct {
+		name        string
+		units       darksky.Units
+		temperature float64
+		humidity    float64
+		expected    float64
+	}{
+		// Reference values are from the US National Weather Service's heat
+		// index chart.
+		{
+			name:        "90F_70pct",
+			units:       darksky.UnitsUS,
+			temperature: 90,
+			humidity:    0.7,
+			expected:    106,
+		},
+		{
+			name:        "100F_40pct",
+			units:       darksky.UnitsUS,
+			temperature: 100,
+			humidity:    0.4,
+			expected:    109,
+		},
+		{
+			name:        "96F_65pct",
+			units:       darksky.UnitsUS,
+			temperature: 96,
+			humidity:    0.65,
+			expected:    121,
+		},
+		{
+			name:        "si_90F_70pct",
+			units:       darksky.UnitsSI,
+			temperature: (90 - 32) * 5.0 / 9,
+			humidity:    0.7,
+			expected:    (106 - 32) * 5.0 / 9,
+		},
+		{
+			name:        "mild",
+			units:       darksky.UnitsUS,
+			temperature: 70,
+			humidity:    0.5,
+			expected:    69.5,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			c := &darksky.Currently{
+				Humidity:    tc.humidity,
+				Temperature: tc.temperature,
+			}
+			actual, err := c.HeatIndex(tc.units)
+			require.NoError(t, err)
+			assert.InDelta(t, tc.expected, actual, 0.6)
+
+			h := &darksky.HourlyData{
+				Humidity:    tc.humidity,
+				Temperature: tc.temperature,
+			}
+			actual, err = h.HeatIndex(tc.units)
+			require.NoError(t, err)
+			assert.InDelta(t, tc.expected, actual, 0.6)
+		})
+	}
+}
+
+func TestHumidexAndRelativeHumidity(t *testing.T) {
+	for _, tc := range []struct {
+		name                     string
+		units                    darksky.Units
+		temperature              float64
+		dewPoint                 float64
+		expectedHumidex          float64
+		expectedRelativeHumidity float64
+	}{
+		// Humidex reference values are rounded to the nearest degree, as in
+		// Environment Canada's humidex table.
+		{
+			name:                     "30C_15C",
+			units:                    darksky.UnitsSI,
+			temperature:              30,
+			dewPoint:                 15,
+			expectedHumidex:          34,
+			expectedRelativeHumidity: 0.40,
+		},
+		{
+			name:                     "35C_25C",
+			units:                    darksky.UnitsCA,
+			temperature:              35,
+			dewPoint:                 25,
+			expectedHumidex:          47,
+			expectedRelativeHumidity: 0.56,
+		},
+		{
+			name:                     "20C_10C",
+			units:                    darksky.UnitsUK2,
+			temperature:              20,
+			dewPoint:                 10,
+			expectedHumidex:          21,
+			expectedRelativeHumidity: 0.53,
+		},
+		{
+			name:                     "86F_59F",
+			units:                    darksky.UnitsUS,
+			temperature:              86,
+			dewPoint:                 59,
+			expectedHumidex:          34*9.0/5 + 32,
+			expectedRelativeHumidity: 0.40,
+		},
+		{
+			name:                     "saturated",
+			units:                    darksky.UnitsSI,
+			temperature:              10,
+			dewPoint:                 10,
+			expectedHumidex:          11,
+			expectedRelativeHumidity: 1,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			c := &darksky.Currently{
+				DewPoint:    tc.dewPoint,
+				Temperature: tc.temperature,
+			}
+			actualHumidex, err := c.Humidex(tc.units)
+			require.NoError(t, err)
+			assert.InDelta(t, tc.expectedHumidex, actualHumidex, 0.6)
+			actualRelativeHumidity, err := c.RelativeHumidity(tc.units)
+			require.NoError(t, err)
+			assert.InDelta(t, tc.expectedRelativeHumidity, actualRelativeHumidity, 0.01)
+
+			h := &darksky.HourlyData{
+				DewPoint:    tc.dewPoint,
+				Temperature: tc.temperature,
+			}
+			actualHumidex, err = h.Humidex(tc.units)
+			require.NoError(t, err)
+			assert.InDelta(t, tc.expectedHumidex, actualHumidex, 0.6)
+			actualRelativeHumidity, err = h.RelativeHumidity(tc.units)
+			require.NoError(t, err)
+			assert.InDelta(t, tc.expectedRelativeHumidity, actualRelativeHumidity, 0.01)
+		})
+	}
+}
+
+func TestBeaufort(t *testing.T) {
+	for _, tc := range []struct {
+		name      string
+		units     darksky.Units
+		windSpeed float64
+		expected  int
+	}{
+		{name: "calm", units: darksky.UnitsSI, windSpeed: 0, expected: 0},
+		{name: "light_air", units: darksky.UnitsSI, windSpeed: 0.5, expected: 1},
+		{name: "fresh_breeze", units: darksky.UnitsSI, windSpeed: 10, expected: 5},
+		{name: "strong_breeze_mph", units: darksky.UnitsUS, windSpeed: 30, expected: 6},
+		{name: "gale_kmh", units: darksky.UnitsCA, windSpeed: 65, expected: 8},
+		{name: "hurricane_force_kmh", units: darksky.UnitsCA, windSpeed: 120, expected: 12},
+		{name: "moderate_breeze_uk2", units: darksky.UnitsUK2, windSpeed: 15, expected: 4},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			for _, f := range []func(darksky.Units) (int, error){
+				(&darksky.Currently{WindSpeed: tc.windSpeed}).Beaufort,
+				(&darksky.DailyData{WindSpeed: tc.windSpeed}).Beaufort,
+				(&darksky.HourlyData{WindSpeed: tc.windSpeed}).Beaufort,
+			} {
+				actual, err := f(tc.units)
+				require.NoError(t, err)
+				assert.Equal(t, tc.expected, actual)
+			}
+		})
+	}
+}
+
+func TestCardinalWindDirection(t *testing.T) {
+	for _, tc := range []struct {
+		bearing  float64
+		expected string
+	}{
+		{bearing: 0, expected: "N"},
+		{bearing: 11.24, expected: "N"},
+		{bearing: 11.25, expected: "NNE"},
+		{bearing: 45, expected: "NE"},
+		{bearing: 90, expected: "E"},
+		{bearing: 191.25, expected: "SSW"},
+		{bearing: 270, expected: "W"},
+		{bearing: 348.75, expected: "N"},
+		{bearing: 360, expected: "N"},
+	} {
+		assert.Equal(t, tc.expected, (&darksky.Currently{WindBearing: tc.bearing}).CardinalWindDirection())
+		assert.Equal(t, tc.expected, (&darksky.DailyData{WindBearing: tc.bearing}).CardinalWindDirection())
+		assert.Equal(t, tc.expected, (&darksky.HourlyData{WindBearing: tc.bearing}).CardinalWindDirection())
+	}
+}
+
+func TestDailyDataDaylight(t *testing.T) {
+	sunrise := time.Unix(1556715966, 0)
+	sunset := time.Unix(1556765158, 0)
+	assert.Equal(t, 13*time.Hour+39*time.Minute+52*time.Second, (&darksky.DailyData{
+		SunriseTime: &darksky.Time{Time: sunrise},
+		SunsetTime:  &darksky.Time{Time: sunset},
+	}).Daylight())
+	assert.Equal(t, time.Duration(0), (&darksky.DailyData{
+		SunriseTime: &darksky.Time{Time: sunrise},
+	}).Daylight())
+}
+
+func TestDerivedUnsupportedUnits(t *testing.T) {
+	c := &darksky.Currently{}
+	_, err := c.WindChill(darksky.UnitsAuto)
+	assert.Error(t, err)
+	_, err = c.HeatIndex(darksky.UnitsAuto)
+	assert.Error(t, err)
+	_, err = c.Humidex(darksky.UnitsAuto)
+	assert.Error(t, err)
+	_, err = c.RelativeHumidity(darksky.UnitsAuto)
+	assert.Error(t, err)
+	_, err = c.Beaufort(darksky.UnitsAuto)
+	assert.Error(t, err)
+}
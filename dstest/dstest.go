@@ -10,6 +10,7 @@ import (
 	"sort"
 	"strconv"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/go-chi/chi"
@@ -38,12 +39,25 @@ type Request struct {
 	Units     darksky.Units
 }
 
+// A Response is a scripted response. Scripted responses are returned, in
+// order, before any forecast is looked up.
+type Response struct {
+	StatusCode      int
+	Header          http.Header
+	Body            string
+	CloseConnection bool // If true, close the connection without responding.
+}
+
 // A Server is a mock server.
 type Server struct {
 	*httptest.Server
 	chi.Router
 	Key       string
 	Forecasts map[Request]string
+
+	mutex            sync.Mutex
+	responses        []Response
+	forecastAPICalls int
 }
 
 // An Option sets an option on a Server.
@@ -67,6 +81,13 @@ func WithForecast(request Request, forecastStr string) Option {
 	}
 }
 
+// WithResponses returns an option that scripts responses on a Server.
+func WithResponses(responses ...Response) Option {
+	return func(s *Server) {
+		s.AddResponses(responses...)
+	}
+}
+
 // NewServer returns a new Server.
 func NewServer(options ...Option) *Server {
 	router := chi.NewRouter()
@@ -95,7 +116,43 @@ func (s *Server) NewClient(options ...darksky.ClientOption) (*darksky.Client, er
 	)
 }
 
+// ForecastAPICalls returns the number of successful forecast responses, as
+// reported in the X-Forecast-API-Calls header.
+func (s *Server) ForecastAPICalls() int {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	return s.forecastAPICalls
+}
+
+// SetForecastAPICalls sets the number of forecast API calls already made, for
+// example to simulate calls made by other clients.
+func (s *Server) SetForecastAPICalls(forecastAPICalls int) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.forecastAPICalls = forecastAPICalls
+}
+
+// AddResponses appends responses to the scripted responses.
+func (s *Server) AddResponses(responses ...Response) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.responses = append(s.responses, responses...)
+}
+
+// SetForecast sets the response to request to forecastStr. Unlike modifying
+// Forecasts directly, it is safe to call while s is serving requests.
+func (s *Server) SetForecast(request Request, forecastStr string) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.Forecasts[request] = forecastStr
+}
+
 func (s *Server) handleForecast(w http.ResponseWriter, r *http.Request) {
+	if response, ok := s.nextResponse(); ok {
+		writeResponse(w, response)
+		return
+	}
+
 	if chi.URLParam(r, "key") != s.Key {
 		w.WriteHeader(http.StatusUnauthorized)
 		return
@@ -143,14 +200,31 @@ func (s *Server) handleForecast(w http.ResponseWriter, r *http.Request) {
 		request.Units = darksky.Units(units)
 	}
 
+	s.mutex.Lock()
 	forecast, ok := s.Forecasts[request]
 	if !ok {
+		s.mutex.Unlock()
 		w.WriteHeader(http.StatusNotFound)
 		return
 	}
+	s.forecastAPICalls++
+	forecastAPICalls := s.forecastAPICalls
+	s.mutex.Unlock()
+	w.Header().Set("X-Forecast-API-Calls", strconv.Itoa(forecastAPICalls))
 	_, _ = w.Write([]byte(forecast))
 }
 
+func (s *Server) nextResponse() (Response, bool) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	if len(s.responses) == 0 {
+		return Response{}, false
+	}
+	response := s.responses[0]
+	s.responses = s.responses[1:]
+	return response, true
+}
+
 // Options returns a new darksky.ForecastOptions constructed from r.
 func (r *Request) Options() *darksky.ForecastOptions {
 	if r.Exclude == "" && r.Extend == darksky.DefaultExtend && r.Lang == darksky.DefaultLang && r.Units == darksky.DefaultUnits {
@@ -174,6 +248,29 @@ func (r *Request) Options() *darksky.ForecastOptions {
 	return o
 }
 
+func writeResponse(w http.ResponseWriter, response Response) {
+	if response.CloseConnection {
+		if hijacker, ok := w.(http.Hijacker); ok {
+			if conn, _, err := hijacker.Hijack(); err == nil {
+				_ = conn.Close()
+				return
+			}
+		}
+		panic(http.ErrAbortHandler)
+	}
+	for key, values := range response.Header {
+		for _, value := range values {
+			w.Header().Add(key, value)
+		}
+	}
+	statusCode := response.StatusCode
+	if statusCode == 0 {
+		statusCode = http.StatusOK
+	}
+	w.WriteHeader(statusCode)
+	_, _ = w.Write([]byte(response.Body))
+}
+
 func parseFloatFromURLParam(r *http.Request, key string, min, max float64) (float64, error) {
 	x, err := strconv.ParseFloat(chi.URLParam(r, key), 64)
 	if err != nil {
@@ -153,3 +153,21 @@ func TestServer(t *testing.T) {
 		})
 	}
 }
+
+func TestServerForecastAPICalls(t *testing.T) {
+	s := dstest.NewServer(
+		dstest.WithDefaultForecasts(),
+	)
+	s.SetForecastAPICalls(10)
+	var forecastAPICalls int
+	c, err := s.NewClient(
+		darksky.WithResponseMetadataCallback(func(rm *darksky.ResponseMetadata) {
+			forecastAPICalls = rm.ForecastAPICalls
+		}),
+	)
+	require.NoError(t, err)
+	_, err = c.Forecast(context.Background(), 34.0219, -118.4814, nil, &darksky.ForecastOptions{Lang: darksky.LangFR})
+	require.NoError(t, err)
+	assert.Equal(t, 11, forecastAPICalls)
+	assert.Equal(t, 11, s.ForecastAPICalls())
+}
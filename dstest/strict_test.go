@@ -0,0 +1,21 @@
+package dstest
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/twpayne/go-darksky"
+)
+
+// TestDefaultForecastsStrict tests that the default forecasts do not contain
+// any fields that are not modeled by darksky.Forecast.
+func TestDefaultForecastsStrict(t *testing.T) {
+	for request, forecastStr := range defaultForecasts {
+		forecast, err := darksky.ParseForecast(strings.NewReader(forecastStr), true)
+		require.NoError(t, err)
+		assert.Empty(t, forecast.UnknownFields, "%+v", request)
+	}
+}
@@ -2,11 +2,15 @@ package darksky
 
 import (
 	"context"
+	"database/sql/driver"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io/ioutil"
+	"math"
 	"net/http"
 	"net/url"
+	"reflect"
 	"sort"
 	"strconv"
 	"strings"
@@ -182,6 +186,20 @@ const (
 	IconWind              Icon = "wind"
 )
 
+// Icons is a slice of all icons.
+var Icons = []Icon{
+	IconClearDay,
+	IconClearNight,
+	IconCloudy,
+	IconFog,
+	IconPartlyCloudyDay,
+	IconPartlyCloudyNight,
+	IconRain,
+	IconSleet,
+	IconSnow,
+	IconWind,
+}
+
 // A PrecipType is a type of precipitation.
 type PrecipType string
 
@@ -202,6 +220,10 @@ const (
 	SeverityWarning  Severity = "warning"
 )
 
+// ErrUnsupportedScanType is returned by Time.Scan when the source value has an
+// unsupported type.
+var ErrUnsupportedScanType = errors.New("darksky: unsupported scan type")
+
 // A Time is a time that unmarshals from a UNIX timestamp.
 type Time struct {
 	time.Time
@@ -228,25 +250,30 @@ type Alert struct {
 
 // A Currently is a current observation.
 type Currently struct {
-	ApparentTemperature  float64 `json:"apparentTemperature"`
-	CloudCover           float64 `json:"cloudCover"`
-	DewPoint             float64 `json:"dewPoint"`
-	Humidity             float64 `json:"humidity"`
-	Icon                 Icon    `json:"icon"`
-	NearestStormBearing  float64 `json:"nearestStormBearing"`
-	NearestStormDistance float64 `json:"nearestStormDistance"`
-	Ozone                float64 `json:"ozone"`
-	PrecipIntensity      float64 `json:"precipIntensity"`
-	PrecipProbability    float64 `json:"precipProbability"`
-	Pressure             float64 `json:"pressure"`
-	Summary              string  `json:"summary"`
-	Temperature          float64 `json:"temperature"`
-	Time                 *Time   `json:"time"`
-	UVIndex              float64 `json:"uvIndex"`
-	Visibility           float64 `json:"visibility"`
-	WindBearing          float64 `json:"windBearing"`
-	WindGust             float64 `json:"windGust"`
-	WindSpeed            float64 `json:"windSpeed"`
+	ApparentTemperature  float64    `json:"apparentTemperature"`
+	CloudCover           float64    `json:"cloudCover"`
+	DewPoint             float64    `json:"dewPoint"`
+	Humidity             float64    `json:"humidity"`
+	Icon                 Icon       `json:"icon"`
+	NearestStormBearing  float64    `json:"nearestStormBearing"`
+	NearestStormDistance float64    `json:"nearestStormDistance"`
+	Ozone                float64    `json:"ozone"`
+	PrecipAccumulation   float64    `json:"precipAccumulation"`
+	PrecipIntensity      float64    `json:"precipIntensity"`
+	PrecipIntensityError float64    `json:"precipIntensityError"`
+	PrecipProbability    float64    `json:"precipProbability"`
+	PrecipType           PrecipType `json:"precipType"`
+	Pressure             float64    `json:"pressure"`
+	Summary              string     `json:"summary"`
+	Temperature          float64    `json:"temperature"`
+	Time                 *Time      `json:"time"`
+	UVIndex              float64    `json:"uvIndex"`
+	Visibility           float64    `json:"visibility"`
+	WindBearing          float64    `json:"windBearing"`
+	WindGust             float64    `json:"windGust"`
+	WindSpeed            float64    `json:"windSpeed"`
+
+	present fieldSet
 }
 
 // DailyData are daily forecast data.
@@ -258,14 +285,16 @@ type DailyData struct {
 	ApparentTemperatureMax      float64    `json:"apparentTemperatureMax"`
 	ApparentTemperatureMaxTime  *Time      `json:"apparentTemperatureMaxTime"`
 	ApparentTemperatureMin      float64    `json:"apparentTemperatureMin"`
-	ApparentTemperatureMinTime  float64    `json:"apparentTemperatureMinTime"`
+	ApparentTemperatureMinTime  *Time      `json:"apparentTemperatureMinTime"`
 	CloudCover                  float64    `json:"cloudCover"`
 	DewPoint                    float64    `json:"dewPoint"`
 	Humidity                    float64    `json:"humidity"`
 	Icon                        Icon       `json:"icon"`
 	MoonPhase                   float64    `json:"moonPhase"`
 	Ozone                       float64    `json:"ozone"`
+	PrecipAccumulation          float64    `json:"precipAccumulation"`
 	PrecipIntensity             float64    `json:"precipIntensity"`
+	PrecipIntensityError        float64    `json:"precipIntensityError"`
 	PrecipIntensityMax          float64    `json:"precipIntensityMax"`
 	PrecipIntensityMaxTime      *Time      `json:"precipIntensityMaxTime"`
 	PrecipProbability           float64    `json:"precipProbability"`
@@ -290,6 +319,8 @@ type DailyData struct {
 	WindGust                    float64    `json:"windGust"`
 	WindGustTime                *Time      `json:"windGustTime"`
 	WindSpeed                   float64    `json:"windSpeed"`
+
+	present fieldSet
 }
 
 // A Daily is a daily forecast.
@@ -301,7 +332,14 @@ type Daily struct {
 
 // Flags are forecast flags.
 type Flags struct {
+	DarkSkyStations    []string    `json:"darksky-stations"`
 	DarkSkyUnavailable interface{} `json:"darksky-unavailable"`
+	DataPointStations  []string    `json:"datapoint-stations"`
+	ISDStations        []string    `json:"isd-stations"`
+	LAMPStations       []string    `json:"lamp-stations"`
+	MADISStations      []string    `json:"madis-stations"`
+	MeteoAlarmLicense  string      `json:"meteoalarm-license"`
+	METNOLicense       string      `json:"metno-license"`
 	NearestStation     float64     `json:"nearest-station"`
 	Sources            []string    `json:"sources"`
 	Units              Units       `json:"units"`
@@ -309,24 +347,29 @@ type Flags struct {
 
 // HourlyData are hourly forecast data.
 type HourlyData struct {
-	ApparentTemperature float64    `json:"apparentTemperature"`
-	CloudCover          float64    `json:"cloudCover"`
-	DewPoint            float64    `json:"dewPoint"`
-	Humidity            float64    `json:"humidity"`
-	Icon                Icon       `json:"icon"`
-	Ozone               float64    `json:"ozone"`
-	PrecipIntensity     float64    `json:"precipIntensity"`
-	PrecipProbability   float64    `json:"precipProbability"`
-	PrecipType          PrecipType `json:"precipType"`
-	Pressure            float64    `json:"pressure"`
-	Summary             string     `json:"summary"`
-	Temperature         float64    `json:"temperature"`
-	Time                *Time      `json:"time"`
-	UVIndex             float64    `json:"uvIndex"`
-	Visibility          float64    `json:"visibility"`
-	WindBearing         float64    `json:"windBearing"`
-	WindGust            float64    `json:"windGust"`
-	WindSpeed           float64    `json:"windSpeed"`
+	ApparentTemperature  float64    `json:"apparentTemperature"`
+	CloudCover           float64    `json:"cloudCover"`
+	DewPoint             float64    `json:"dewPoint"`
+	Humidity             float64    `json:"humidity"`
+	Icon                 Icon       `json:"icon"`
+	Ozone                float64    `json:"ozone"`
+	PrecipAccumulation   float64    `json:"precipAccumulation"`
+	PrecipIntensity      float64    `json:"precipIntensity"`
+	PrecipIntensityError float64    `json:"precipIntensityError"`
+	PrecipProbability    float64    `json:"precipProbability"`
+	PrecipType           PrecipType `json:"precipType"`
+	Pressure             float64    `json:"pressure"`
+	Summary              string     `json:"summary"`
+	Temperature          float64    `json:"temperature"`
+	Time                 *Time      `json:"time"`
+	UVIndex              float64    `json:"uvIndex"`
+	Visibility           float64    `json:"visibility"`
+	WindBearing          float64    `json:"windBearing"`
+	WindGust             float64    `json:"windGust"`
+	WindGustTime         *Time      `json:"windGustTime"`
+	WindSpeed            float64    `json:"windSpeed"`
+
+	present fieldSet
 }
 
 // An Hourly is an hourly forecast.
@@ -343,6 +386,8 @@ type MinutelyData struct {
 	PrecipProbability    float64    `json:"precipProbability"`
 	PrecipType           PrecipType `json:"precipType"`
 	Time                 *Time      `json:"time"`
+
+	present fieldSet
 }
 
 // A Minutely is a minutely forecast.
@@ -352,7 +397,8 @@ type Minutely struct {
 	Summary string          `json:"summary"`
 }
 
-// A Forecast is a forecast.
+// A Forecast is a forecast. All Times in a decoded Forecast are in the
+// forecast's location.
 type Forecast struct {
 	Alerts    []*Alert   `json:"alerts"`
 	Currently *Currently `json:"currently"`
@@ -364,11 +410,20 @@ type Forecast struct {
 	Minutely  *Minutely  `json:"minutely"`
 	Offset    float64    `json:"offset"`
 	Timezone  string     `json:"timezone"`
+
+	UnknownFields []*UnknownField `json:"-"`
+
+	location *time.Location
 }
 
 // Forecast returns the forecast for latitude and longitude at time t. If t is
 // nil or zero then a forecast request is sent. If t is non-nil and non-zero
 // then a time machine request is sent.
+//
+// Concurrent calls for the same request are coalesced into a single API call,
+// which is canceled only when every caller's ctx is done. A caller whose ctx is
+// done returns ctx.Err() without affecting the other callers. Each caller
+// receives its own *Forecast.
 func (c *Client) Forecast(ctx context.Context, latitude, longitude float64, t *Time, options *ForecastOptions) (*Forecast, error) {
 	urlStr := fmt.Sprintf("%s/forecast/%s/%f,%f", c.baseURL, c.key, latitude, longitude)
 	if t != nil && !t.IsZero() {
@@ -397,25 +452,91 @@ func (c *Client) Forecast(ctx context.Context, latitude, longitude float64, t *T
 		urlStr += "?" + values.Encode()
 	}
 
-	req, err := http.NewRequest(http.MethodGet, urlStr, nil)
+	cacheKey := forecastCacheKey(latitude, longitude, t, options)
+	if c.cache != nil {
+		if body, ok := c.cache.Get(cacheKey); ok {
+			if respValue, err := c.decodeForecast(body); err == nil {
+				return respValue, nil
+			}
+		}
+	}
+
+	body, err := c.flights.do(ctx, cacheKey, func(ctx context.Context) ([]byte, error) {
+		return c.fetch(ctx, urlStr)
+	})
 	if err != nil {
 		return nil, err
 	}
+
+	respValue, err := c.decodeForecast(body)
+	if err != nil {
+		return respValue, err
+	}
+	if ttl, ok := c.cacheTTL(t); c.cache != nil && ok {
+		c.cache.Set(cacheKey, body, ttl)
+	}
+	return respValue, nil
+}
+
+// decodeForecast decodes a Forecast from body.
+func (c *Client) decodeForecast(body []byte) (*Forecast, error) {
+	forecast, err := parseForecast(body, c.strictDecoding)
+	if err != nil {
+		return forecast, err
+	}
+	if c.optionalFields {
+		if err := forecast.decodeOptionalFields(body); err != nil {
+			return forecast, err
+		}
+	}
+	return forecast, nil
+}
+
+// fetch returns the body of a successful response to a GET request for urlStr.
+func (c *Client) fetch(ctx context.Context, urlStr string) ([]byte, error) {
+	req, err := http.NewRequest(http.MethodGet, urlStr, nil)
+	if err != nil {
+		return nil, redactURLError(err)
+	}
 	req = req.WithContext(ctx)
 	req.Header.Set("Accept", "application/json")
-	resp, err := c.httpClient.Do(req)
+	resp, err := c.do(req)
 	if err != nil {
 		return nil, err
 	}
 	defer resp.Body.Close()
+	return ioutil.ReadAll(resp.Body)
+}
 
-	if c.responseMetadataCallback != nil {
-		var forecastAPICalls int
-		if facStr := resp.Header.Get("X-Forecast-API-Calls"); facStr != "" {
-			if fac, err := strconv.ParseInt(facStr, 10, 64); err == nil {
-				forecastAPICalls = int(fac)
+// doOnce sends req once. attempt is the attempt number, starting at 1. On
+// success, the caller is responsible for closing the response body.
+func (c *Client) doOnce(req *http.Request, attempt int) (*http.Response, error) {
+	if err := c.beforeAttempt(req.Context()); err != nil {
+		return nil, err
+	}
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		c.releaseQuota()
+		return nil, redactURLError(err)
+	}
+	success := http.StatusOK <= resp.StatusCode && resp.StatusCode < http.StatusMultipleChoices
+	if !success {
+		// Release the reservation before observing X-Forecast-API-Calls, so
+		// that the count is only increased if the API billed the call.
+		c.releaseQuota()
+	}
+
+	var forecastAPICalls int
+	if facStr := resp.Header.Get("X-Forecast-API-Calls"); facStr != "" {
+		if fac, err := strconv.ParseInt(facStr, 10, 64); err == nil {
+			forecastAPICalls = int(fac)
+			if c.quota != nil {
+				c.quota.observe(c.now(), forecastAPICalls)
 			}
 		}
+	}
+
+	if c.responseMetadataCallback != nil {
 		var responseTime time.Duration
 		if rtStr := resp.Header.Get("X-Response-Time"); rtStr != "" {
 			if rt, err := time.ParseDuration(rtStr); err == nil {
@@ -423,13 +544,15 @@ func (c *Client) Forecast(ctx context.Context, latitude, longitude float64, t *T
 			}
 		}
 		c.responseMetadataCallback(&ResponseMetadata{
+			URL:              redactURL(req.URL.String()),
 			StatusCode:       resp.StatusCode,
 			ForecastAPICalls: forecastAPICalls,
 			ResponseTime:     responseTime,
+			Attempt:          attempt,
 		})
 	}
 
-	if resp.StatusCode < http.StatusOK || http.StatusMultipleChoices <= resp.StatusCode {
+	if !success {
 		respBody, err := ioutil.ReadAll(resp.Body)
 		e := &Error{
 			Request:      req,
@@ -439,25 +562,188 @@ func (c *Client) Forecast(ctx context.Context, latitude, longitude float64, t *T
 		if err == nil {
 			_ = json.Unmarshal(respBody, &e.Details)
 		}
+		resp.Body.Close()
 		return nil, e
 	}
 
-	respValue := &Forecast{}
-	return respValue, json.NewDecoder(resp.Body).Decode(respValue)
+	return resp, nil
+}
+
+// redactURLError redacts the key from err if err is a *url.Error.
+func redactURLError(err error) error {
+	if urlErr, ok := err.(*url.Error); ok {
+		urlErr.URL = redactURL(urlErr.URL)
+	}
+	return err
+}
+
+// Location returns the location of f's timezone. If f's timezone cannot be
+// loaded then it returns a fixed zone with f's offset. The location of a
+// decoded forecast is loaded once, when it is decoded.
+func (f *Forecast) Location() *time.Location {
+	if f.location != nil {
+		return f.location
+	}
+	return loadLocation(f.Timezone, f.Offset)
+}
+
+// loadLocation returns the location of timezone, or a fixed zone with offset
+// hours if timezone cannot be loaded.
+func loadLocation(timezone string, offset float64) *time.Location {
+	if timezone != "" {
+		if loc, err := time.LoadLocation(timezone); err == nil {
+			return loc
+		}
+	}
+	return time.FixedZone(timezone, int(offset*60*60))
+}
+
+// setTimeLocations sets the location of all Times reachable from v to loc.
+func setTimeLocations(v reflect.Value, loc *time.Location) {
+	switch v.Kind() {
+	case reflect.Ptr:
+		if !v.IsNil() {
+			setTimeLocations(v.Elem(), loc)
+		}
+	case reflect.Slice:
+		switch v.Type().Elem().Kind() {
+		case reflect.Ptr, reflect.Slice, reflect.Struct:
+			for i := 0; i < v.Len(); i++ {
+				setTimeLocations(v.Index(i), loc)
+			}
+		}
+	case reflect.Struct:
+		if t, ok := v.Addr().Interface().(*Time); ok {
+			t.Time = t.Time.In(loc)
+			return
+		}
+		for i := 0; i < v.NumField(); i++ {
+			if v.Type().Field(i).PkgPath == "" {
+				setTimeLocations(v.Field(i), loc)
+			}
+		}
+	}
 }
 
 // UnmarshalJSON implements the json.Unmarshaler interface. The time is expected
-// to be a UNIX timestamp in seconds.
+// to be a UNIX timestamp in seconds, which may be fractional, or null, which
+// is unmarshaled as the zero time.
 func (t *Time) UnmarshalJSON(data []byte) error {
+	if string(data) == "null" {
+		t.Time = time.Time{}
+		return nil
+	}
 	var sec int64
 	if err := json.Unmarshal(data, &sec); err != nil {
-		return err
+		var fsec float64
+		if json.Unmarshal(data, &fsec) != nil {
+			return err
+		}
+		t.Time = timeFromFloatSeconds(fsec)
+		return nil
 	}
 	t.Time = time.Unix(sec, 0)
 	return nil
 }
 
-// MarshalJSON implements the json.Marshaler interface.
-func (t *Time) MarshalJSON() ([]byte, error) {
-	return json.Marshal(t.Unix())
+// MarshalJSON implements the json.Marshaler interface. The zero time is
+// marshaled as null.
+func (t Time) MarshalJSON() ([]byte, error) {
+	if t.IsZero() {
+		return []byte("null"), nil
+	}
+	return []byte(formatSeconds(t.Time)), nil
+}
+
+// UnmarshalText implements the encoding.TextUnmarshaler interface. The text
+// may be an RFC 3339 time or a UNIX timestamp in seconds, which may be
+// fractional. Empty text is unmarshaled as the zero time.
+func (t *Time) UnmarshalText(text []byte) error {
+	s := string(text)
+	if s == "" {
+		t.Time = time.Time{}
+		return nil
+	}
+	if sec, err := strconv.ParseInt(s, 10, 64); err == nil {
+		t.Time = time.Unix(sec, 0)
+		return nil
+	}
+	if fsec, err := strconv.ParseFloat(s, 64); err == nil {
+		t.Time = timeFromFloatSeconds(fsec)
+		return nil
+	}
+	tm, err := time.Parse(time.RFC3339Nano, s)
+	if err != nil {
+		return err
+	}
+	t.Time = tm
+	return nil
+}
+
+// MarshalText implements the encoding.TextMarshaler interface. The time is
+// marshaled in RFC 3339 format, and the zero time is marshaled as empty text.
+func (t Time) MarshalText() ([]byte, error) {
+	if t.IsZero() {
+		return []byte{}, nil
+	}
+	return t.Time.MarshalText()
+}
+
+// Scan implements the database/sql.Scanner interface. NULL is scanned as the
+// zero time.
+func (t *Time) Scan(src interface{}) error {
+	switch src := src.(type) {
+	case nil:
+		t.Time = time.Time{}
+	case time.Time:
+		t.Time = src
+	case int64:
+		t.Time = time.Unix(src, 0)
+	case float64:
+		t.Time = timeFromFloatSeconds(src)
+	case []byte:
+		return t.UnmarshalText(src)
+	case string:
+		return t.UnmarshalText([]byte(src))
+	default:
+		return fmt.Errorf("%w: cannot scan %T into Time", ErrUnsupportedScanType, src)
+	}
+	return nil
+}
+
+// Value implements the database/sql/driver.Valuer interface. The zero time is
+// NULL.
+func (t Time) Value() (driver.Value, error) {
+	if t.IsZero() {
+		return nil, nil
+	}
+	return t.Time, nil
+}
+
+// formatSeconds formats t as a UNIX timestamp in seconds, with a fractional
+// part only if needed.
+func formatSeconds(t time.Time) string {
+	sec, nsec := t.Unix(), t.Nanosecond()
+	if nsec == 0 {
+		return strconv.FormatInt(sec, 10)
+	}
+	if sec < 0 {
+		sec++
+		nsec = 1e9 - nsec
+		s := fmt.Sprintf("%d.%09d", sec, nsec)
+		if sec == 0 {
+			s = "-" + s
+		}
+		return strings.TrimRight(s, "0")
+	}
+	return strings.TrimRight(fmt.Sprintf("%d.%09d", sec, nsec), "0")
+}
+
+// timeFromFloatSeconds returns the time corresponding to a UNIX timestamp in
+// fractional seconds, rounded to the nearest microsecond to avoid floating
+// point artifacts.
+func timeFromFloatSeconds(fsec float64) time.Time {
+	sec := math.Floor(fsec)
+	nsec := math.Round((fsec-sec)*1e6) * 1e3
+	return time.Unix(int64(sec), int64(nsec))
 }
@@ -1,10 +1,16 @@
 package darksky
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
+	"errors"
+	"io/ioutil"
 	"net/http"
 	"net/http/httptest"
 	"os"
+	"reflect"
+	"strings"
 	"testing"
 	"time"
 
@@ -49,7 +55,7 @@ func TestClientInternalServerError(t *testing.T) {
 	e, ok := err.(*Error)
 	require.True(t, ok)
 	assert.Equal(t, http.StatusInternalServerError, e.Response.StatusCode)
-	assert.Equal(t, s.URL+"/forecast/key/42.360100,-71.058900: 500 Internal Server Error: response body", e.Error())
+	assert.Equal(t, s.URL+"/forecast/REDACTED/42.360100,-71.058900: 500 Internal Server Error: response body", e.Error())
 }
 
 func TestClientForecastOptions(t *testing.T) {
@@ -134,6 +140,78 @@ func TestClientRequestFail(t *testing.T) {
 	assert.Contains(t, err.Error(), "connection refused")
 }
 
+// TestForecastComplete tests that a forecast containing every documented field
+// decodes strictly and that every field is populated.
+func TestForecastComplete(t *testing.T) {
+	data, err := ioutil.ReadFile("testdata/complete.json")
+	require.NoError(t, err)
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+	forecast := &Forecast{}
+	require.NoError(t, decoder.Decode(forecast))
+	assertNoZeroFields(t, "forecast", reflect.ValueOf(forecast))
+
+	assert.Equal(t, 0.21, forecast.Currently.PrecipAccumulation)
+	assert.Equal(t, 0.0045, forecast.Currently.PrecipIntensityError)
+	assert.Equal(t, PrecipTypeSnow, forecast.Currently.PrecipType)
+	assert.Equal(t, 0.21, forecast.Hourly.Data[0].PrecipAccumulation)
+	assert.Equal(t, int64(1556695800), forecast.Hourly.Data[0].WindGustTime.Unix())
+	assert.Equal(t, 1.84, forecast.Daily.Data[0].PrecipAccumulation)
+	assert.Equal(t, 0.0031, forecast.Daily.Data[0].PrecipIntensityError)
+	assert.Equal(t, int64(1556697600), forecast.Daily.Data[0].ApparentTemperatureMinTime.Unix())
+	assert.Equal(t, int64(1556739000), forecast.Daily.Data[0].UVIndexTime.Unix())
+	assert.Equal(t, []string{"KBOX", "KENX"}, forecast.Flags.DarkSkyStations)
+	assert.Equal(t, []string{"725090-14739", "744900-14753"}, forecast.Flags.ISDStations)
+	assert.Equal(t, []string{"AV085", "C0317"}, forecast.Flags.MADISStations)
+	assert.Equal(t, "Based on data from the Norwegian Meteorological Institute. (http://api.met.no/)", forecast.Flags.METNOLicense)
+}
+
+func TestForecastLocation(t *testing.T) {
+	for _, tc := range []struct {
+		name             string
+		data             string
+		expectedLocation string
+		expectedOffset   int
+	}{
+		{
+			name:             "timezone",
+			data:             `{"timezone":"America/New_York","offset":-4,"currently":{"time":1556694000},"daily":{"data":[{"sunriseTime":1556704800}]},"alerts":[{"expires":1556780400}]}`,
+			expectedLocation: "America/New_York",
+			expectedOffset:   -4 * 60 * 60,
+		},
+		{
+			name:             "offset",
+			data:             `{"timezone":"Invalid/Timezone","offset":5.5,"currently":{"time":1556694000},"daily":{"data":[{"sunriseTime":1556704800}]},"alerts":[{"expires":1556780400}]}`,
+			expectedLocation: "Invalid/Timezone",
+			expectedOffset:   11 * 30 * 60,
+		},
+		{
+			name:             "none",
+			data:             `{"currently":{"time":1556694000},"daily":{"data":[{"sunriseTime":1556704800}]},"alerts":[{"expires":1556780400}]}`,
+			expectedLocation: "",
+			expectedOffset:   0,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			forecast, err := ParseForecast(strings.NewReader(tc.data), false)
+			require.NoError(t, err)
+			loc := forecast.Location()
+			assert.Equal(t, tc.expectedLocation, loc.String())
+			assert.True(t, loc == forecast.Location(), "location should be loaded once")
+			for _, tm := range []*Time{
+				forecast.Currently.Time,
+				forecast.Daily.Data[0].SunriseTime,
+				forecast.Alerts[0].Expires,
+			} {
+				assert.True(t, loc == tm.Location())
+				_, offset := tm.Zone()
+				assert.Equal(t, tc.expectedOffset, offset)
+			}
+			assert.Equal(t, int64(1556694000), forecast.Currently.Time.Unix())
+		})
+	}
+}
+
 func TestTimeUnmarshalJSON(t *testing.T) {
 	for _, tc := range []struct {
 		name         string
@@ -146,6 +224,21 @@ func TestTimeUnmarshalJSON(t *testing.T) {
 			data:         []byte("0"),
 			expectedTime: &Time{Time: time.Unix(0, 0)},
 		},
+		{
+			name:         "fractional",
+			data:         []byte("1556694000.25"),
+			expectedTime: &Time{Time: time.Unix(1556694000, 250000000)},
+		},
+		{
+			name:         "negative_fractional",
+			data:         []byte("-1.5"),
+			expectedTime: &Time{Time: time.Unix(-2, 500000000)},
+		},
+		{
+			name:         "null",
+			data:         []byte("null"),
+			expectedTime: &Time{},
+		},
 		{
 			name:        "empty",
 			data:        []byte(""),
@@ -170,6 +263,212 @@ func TestTimeUnmarshalJSON(t *testing.T) {
 	}
 }
 
+// assertNoZeroFields asserts that v and all exported fields, elements, and
+// pointees of v are non-zero. Fields that are not decoded from JSON are
+// ignored.
+func assertNoZeroFields(t *testing.T, path string, v reflect.Value) {
+	t.Helper()
+	switch v.Kind() {
+	case reflect.Ptr, reflect.Interface:
+		if assert.False(t, v.IsNil(), path) {
+			assertNoZeroFields(t, path, v.Elem())
+		}
+	case reflect.Slice:
+		if assert.NotZero(t, v.Len(), path) {
+			for i := 0; i < v.Len(); i++ {
+				assertNoZeroFields(t, path+"[]", v.Index(i))
+			}
+		}
+	case reflect.Struct:
+		if v.Type() == reflect.TypeOf(time.Time{}) {
+			assert.False(t, v.Interface().(time.Time).IsZero(), path)
+			return
+		}
+		for i := 0; i < v.NumField(); i++ {
+			if field := v.Type().Field(i); field.PkgPath == "" && field.Tag.Get("json") != "-" {
+				assertNoZeroFields(t, path+"."+field.Name, v.Field(i))
+			}
+		}
+	default:
+		assert.NotZero(t, v.Interface(), path)
+	}
+}
+
+func TestTimeMarshalJSON(t *testing.T) {
+	for _, tc := range []struct {
+		name     string
+		time     Time
+		expected string
+	}{
+		{
+			name:     "zero",
+			expected: "null",
+		},
+		{
+			name:     "epoch",
+			time:     Time{Time: time.Unix(0, 0)},
+			expected: "0",
+		},
+		{
+			name:     "seconds",
+			time:     Time{Time: time.Unix(1556694000, 0)},
+			expected: "1556694000",
+		},
+		{
+			name:     "fractional",
+			time:     Time{Time: time.Unix(1556694000, 250000000)},
+			expected: "1556694000.25",
+		},
+		{
+			name:     "negative_fractional",
+			time:     Time{Time: time.Unix(-2, 500000000)},
+			expected: "-1.5",
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			data, err := json.Marshal(tc.time)
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, string(data))
+
+			var actual Time
+			require.NoError(t, json.Unmarshal(data, &actual))
+			assert.True(t, tc.time.Equal(actual.Time))
+		})
+	}
+}
+
+func TestTimeJSONPointer(t *testing.T) {
+	type s struct {
+		Time *Time `json:"time"`
+	}
+	var actual s
+	require.NoError(t, json.Unmarshal([]byte(`{"time":null}`), &actual))
+	assert.Nil(t, actual.Time)
+	data, err := json.Marshal(actual)
+	require.NoError(t, err)
+	assert.Equal(t, `{"time":null}`, string(data))
+}
+
+func TestTimeText(t *testing.T) {
+	for _, tc := range []struct {
+		name         string
+		text         string
+		expectedTime time.Time
+		expectedText string
+		expectedErr  bool
+	}{
+		{
+			name: "empty",
+		},
+		{
+			name:         "rfc3339",
+			text:         "2019-05-01T07:00:00Z",
+			expectedTime: time.Unix(1556694000, 0),
+			expectedText: "2019-05-01T07:00:00Z",
+		},
+		{
+			name:         "rfc3339_offset",
+			text:         "2019-05-01T03:00:00.5-04:00",
+			expectedTime: time.Unix(1556694000, 500000000),
+			expectedText: "2019-05-01T03:00:00.5-04:00",
+		},
+		{
+			name:         "seconds",
+			text:         "1556694000",
+			expectedTime: time.Unix(1556694000, 0),
+		},
+		{
+			name:         "fractional_seconds",
+			text:         "1556694000.5",
+			expectedTime: time.Unix(1556694000, 500000000),
+		},
+		{
+			name:        "invalid",
+			text:        "yesterday",
+			expectedErr: true,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			var actual Time
+			err := actual.UnmarshalText([]byte(tc.text))
+			if tc.expectedErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.True(t, tc.expectedTime.Equal(actual.Time))
+			if tc.expectedText != "" || tc.expectedTime.IsZero() {
+				text, err := actual.MarshalText()
+				require.NoError(t, err)
+				assert.Equal(t, tc.expectedText, string(text))
+			}
+		})
+	}
+}
+
+func TestTimeSQL(t *testing.T) {
+	expected := time.Unix(1556694000, 0)
+	for _, tc := range []struct {
+		name         string
+		src          interface{}
+		expectedTime time.Time
+		expectedErr  bool
+	}{
+		{
+			name: "nil",
+		},
+		{
+			name:         "time",
+			src:          expected,
+			expectedTime: expected,
+		},
+		{
+			name:         "int64",
+			src:          int64(1556694000),
+			expectedTime: expected,
+		},
+		{
+			name:         "float64",
+			src:          1556694000.0,
+			expectedTime: expected,
+		},
+		{
+			name:         "bytes",
+			src:          []byte("2019-05-01T07:00:00Z"),
+			expectedTime: expected,
+		},
+		{
+			name:         "string",
+			src:          "1556694000",
+			expectedTime: expected,
+		},
+		{
+			name:        "bool",
+			src:         true,
+			expectedErr: true,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			var actual Time
+			err := actual.Scan(tc.src)
+			if tc.expectedErr {
+				assert.True(t, errors.Is(err, ErrUnsupportedScanType))
+				return
+			}
+			require.NoError(t, err)
+			assert.True(t, tc.expectedTime.Equal(actual.Time))
+
+			value, err := actual.Value()
+			require.NoError(t, err)
+			if tc.expectedTime.IsZero() {
+				assert.Nil(t, value)
+			} else {
+				assert.Equal(t, actual.Time, value)
+			}
+		})
+	}
+}
+
 func mustNewTestClient(t *testing.T, options ...ClientOption) *Client {
 	key := os.Getenv("DARKSKY_KEY")
 	if key == "" {
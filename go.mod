@@ -1,6 +1,6 @@
 module github.com/twpayne/go-darksky
 
-go 1.12
+go 1.13
 
 require (
 	github.com/go-chi/chi v4.1.2+incompatible
@@ -0,0 +1,198 @@
+package darksky
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+)
+
+// ErrEmptyTimeRange is returned by History when from is not before to.
+var ErrEmptyTimeRange = errors.New("darksky: empty time range")
+
+// A History is a continuous series of historical data.
+type History struct {
+	Timezone string
+	Hourly   []*HourlyData
+	Daily    []*DailyData
+}
+
+// A HistoryDayError is an error for a single day in a History.
+type HistoryDayError struct {
+	Day time.Time
+	Err error
+}
+
+// A HistoryError is returned by History when the requests for some days fail.
+type HistoryError struct {
+	Errs []*HistoryDayError
+}
+
+// History returns the hourly and daily data for latitude and longitude from
+// from, inclusive, to to, exclusive. It sends one time machine request for
+// each day, where days are determined in the forecast's timezone. Requests are
+// sent concurrently, subject to the client's concurrency. The returned hourly
+// and daily data are sorted by time and contain no duplicates.
+//
+// If the requests for some days fail then History returns the data for the
+// other days and a *HistoryError. If the request for the first day fails then
+// the other days are determined in from's location, and any day in the
+// forecast's timezone that is not covered by a successful request is reported
+// as failed.
+func (c *Client) History(ctx context.Context, latitude, longitude float64, from, to time.Time, options *ForecastOptions) (*History, error) {
+	if !from.Before(to) {
+		return nil, fmt.Errorf("%w: %s to %s", ErrEmptyTimeRange, from.Format(time.RFC3339), to.Format(time.RFC3339))
+	}
+
+	history := &History{}
+	var forecasts []*Forecast
+	var historyErr HistoryError
+
+	// Request the first day to determine the timezone. If the request fails
+	// then fall back to from's location.
+	loc := from.Location()
+	firstForecast, firstErr := c.Forecast(ctx, latitude, longitude, &Time{Time: from}, options)
+	if firstErr == nil {
+		forecasts = append(forecasts, firstForecast)
+		history.Timezone = firstForecast.Timezone
+		loc = firstForecast.Location()
+	}
+
+	var days []time.Time
+	for day := startOfNextDay(from.In(loc)); day.Before(to); day = startOfNextDay(day) {
+		days = append(days, day)
+	}
+	dayForecasts := make([]*Forecast, len(days))
+	dayErrs := make([]error, len(days))
+	sent := c.parallel(ctx, len(days), func(i int) {
+		dayForecasts[i], dayErrs[i] = c.Forecast(ctx, latitude, longitude, &Time{Time: days[i]}, options)
+	})
+	for i := sent; i < len(days); i++ {
+		dayErrs[i] = ctx.Err()
+	}
+	for i := range days {
+		if dayErrs[i] == nil {
+			forecasts = append(forecasts, dayForecasts[i])
+		}
+	}
+
+	if firstErr == nil {
+		for i, day := range days {
+			if dayErrs[i] != nil {
+				historyErr.Errs = append(historyErr.Errs, &HistoryDayError{
+					Day: day,
+					Err: dayErrs[i],
+				})
+			}
+		}
+	} else {
+		historyErr.Errs = uncoveredDays(from, to, firstErr, days, dayForecasts, dayErrs)
+		for _, forecast := range dayForecasts {
+			if forecast != nil {
+				history.Timezone = forecast.Timezone
+				break
+			}
+		}
+	}
+
+	hourlySeen := make(map[int64]bool)
+	dailySeen := make(map[int64]bool)
+	for _, forecast := range forecasts {
+		if forecast.Hourly != nil {
+			for _, hourlyData := range forecast.Hourly.Data {
+				if hourlyData.Time == nil || hourlyData.Time.Before(from) || !hourlyData.Time.Before(to) {
+					continue
+				}
+				if sec := hourlyData.Time.Unix(); !hourlySeen[sec] {
+					hourlySeen[sec] = true
+					history.Hourly = append(history.Hourly, hourlyData)
+				}
+			}
+		}
+		if forecast.Daily != nil {
+			for _, dailyData := range forecast.Daily.Data {
+				if dailyData.Time == nil || !dailyData.Time.Before(to) {
+					continue
+				}
+				if sec := dailyData.Time.Unix(); !dailySeen[sec] {
+					dailySeen[sec] = true
+					history.Daily = append(history.Daily, dailyData)
+				}
+			}
+		}
+	}
+	sort.Slice(history.Hourly, func(i, j int) bool {
+		return history.Hourly[i].Time.Before(history.Hourly[j].Time.Time)
+	})
+	sort.Slice(history.Daily, func(i, j int) bool {
+		return history.Daily[i].Time.Before(history.Daily[j].Time.Time)
+	})
+
+	if len(historyErr.Errs) != 0 {
+		return history, &historyErr
+	}
+	return history, nil
+}
+
+func (e *HistoryError) Error() string {
+	return fmt.Sprintf("darksky: %d day(s) failed, first: %s: %v", len(e.Errs), e.Errs[0].Day.Format("2006-01-02"), e.Errs[0].Err)
+}
+
+// Unwrap returns the first error.
+func (e *HistoryError) Unwrap() error {
+	return e.Errs[0].Err
+}
+
+// uncoveredDays returns an error for each day from from, inclusive, to to,
+// exclusive, that is not covered by a successful request, when the request for
+// the first day failed with firstErr and the other days were requested at
+// times. Days are determined in the timezone of the first successful response,
+// or in from's location if all requests failed. Days for which a request
+// failed are reported with that request's error, other days with firstErr.
+func uncoveredDays(from, to time.Time, firstErr error, times []time.Time, forecasts []*Forecast, errs []error) []*HistoryDayError {
+	loc := from.Location()
+	for _, forecast := range forecasts {
+		if forecast != nil {
+			loc = forecast.Location()
+			break
+		}
+	}
+	covered := make(map[int64]bool)
+	dayErrs := map[int64]error{
+		startOfDay(from.In(loc)).Unix(): firstErr,
+	}
+	for i, t := range times {
+		day := startOfDay(t.In(loc)).Unix()
+		if errs[i] == nil {
+			covered[day] = true
+		} else if _, ok := dayErrs[day]; !ok {
+			dayErrs[day] = errs[i]
+		}
+	}
+	var historyDayErrs []*HistoryDayError
+	for day := startOfDay(from.In(loc)); day.Before(to); day = startOfNextDay(day) {
+		if covered[day.Unix()] {
+			continue
+		}
+		err, ok := dayErrs[day.Unix()]
+		if !ok {
+			err = firstErr
+		}
+		historyDayErrs = append(historyDayErrs, &HistoryDayError{
+			Day: day,
+			Err: err,
+		})
+	}
+	return historyDayErrs
+}
+
+func startOfDay(t time.Time) time.Time {
+	year, month, day := t.Date()
+	return time.Date(year, month, day, 0, 0, 0, 0, t.Location())
+}
+
+func startOfNextDay(t time.Time) time.Time {
+	year, month, day := t.Date()
+	return time.Date(year, month, day+1, 0, 0, 0, 0, t.Location())
+}
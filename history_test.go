@@ -0,0 +1,188 @@
+package darksky_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/twpayne/go-darksky"
+	"github.com/twpayne/go-darksky/dstest"
+)
+
+func TestClientHistory(t *testing.T) {
+	losAngeles, err := time.LoadLocation("America/Los_Angeles")
+	require.NoError(t, err)
+	day1 := time.Date(2019, 5, 1, 0, 0, 0, 0, losAngeles)
+	day2 := time.Date(2019, 5, 2, 0, 0, 0, 0, losAngeles)
+	day3 := time.Date(2019, 5, 3, 0, 0, 0, 0, losAngeles)
+	from := day1.Add(6 * time.Hour)
+	to := day3.Add(12 * time.Hour)
+
+	for _, tc := range []struct {
+		name               string
+		days               []time.Time
+		expectedHours      int
+		expectedDays       int
+		expectedFailedDays []time.Time
+	}{
+		{
+			name:          "complete",
+			days:          []time.Time{day1, day2, day3},
+			expectedHours: 18 + 24 + 12,
+			expectedDays:  3,
+		},
+		{
+			name:               "missing_day",
+			days:               []time.Time{day1, day3},
+			expectedHours:      18 + 1 + 12, // Day 1 includes the first hour of day 2.
+			expectedDays:       2,
+			expectedFailedDays: []time.Time{day2},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			options := []dstest.Option{}
+			for _, day := range tc.days {
+				requestTime := day
+				if day.Equal(day1) {
+					requestTime = from
+				}
+				options = append(options, withHistoryDay(day, requestTime))
+			}
+			s := dstest.NewServer(options...)
+			defer s.Close()
+			c, err := s.NewClient()
+			require.NoError(t, err)
+
+			history, err := c.History(context.Background(), 34.0219, -118.4814, from, to, nil)
+			if tc.expectedFailedDays == nil {
+				require.NoError(t, err)
+			} else {
+				var historyErr *darksky.HistoryError
+				require.True(t, errors.As(err, &historyErr))
+				require.Len(t, historyErr.Errs, len(tc.expectedFailedDays))
+				for i, day := range tc.expectedFailedDays {
+					assert.True(t, day.Equal(historyErr.Errs[i].Day))
+					assert.True(t, errors.Is(historyErr.Errs[i].Err, darksky.ErrNotFound))
+				}
+			}
+			require.NotNil(t, history)
+			assert.Equal(t, "America/Los_Angeles", history.Timezone)
+			require.Len(t, history.Hourly, tc.expectedHours)
+			require.Len(t, history.Daily, tc.expectedDays)
+			assert.True(t, from.Equal(history.Hourly[0].Time.Time))
+			for i := 1; i < len(history.Hourly); i++ {
+				assert.True(t, history.Hourly[i-1].Time.Before(history.Hourly[i].Time.Time))
+			}
+			assert.True(t, history.Hourly[len(history.Hourly)-1].Time.Before(to))
+			for i := 1; i < len(history.Daily); i++ {
+				assert.True(t, history.Daily[i-1].Time.Before(history.Daily[i].Time.Time))
+			}
+		})
+	}
+}
+
+func TestClientHistoryFirstDayFailed(t *testing.T) {
+	losAngeles, err := time.LoadLocation("America/Los_Angeles")
+	require.NoError(t, err)
+	day1 := time.Date(2019, 5, 1, 0, 0, 0, 0, losAngeles)
+	day3 := time.Date(2019, 5, 3, 0, 0, 0, 0, losAngeles)
+
+	for _, tc := range []struct {
+		name               string
+		loc                *time.Location
+		expectedHours      int
+		expectedDays       int
+		expectedFailedDays []time.Time
+	}{
+		{
+			// Later days are requested at local midnight, so only the first
+			// day is missing.
+			name:               "local",
+			loc:                losAngeles,
+			expectedHours:      24 + 12,
+			expectedDays:       2,
+			expectedFailedDays: []time.Time{day1},
+		},
+		{
+			// Later days are requested at UTC midnight, which is during the
+			// previous local day, so the first day is covered but the last
+			// day is not.
+			name:               "utc",
+			loc:                time.UTC,
+			expectedHours:      18 + 24 + 1,
+			expectedDays:       2,
+			expectedFailedDays: []time.Time{day3},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			from := day1.Add(6 * time.Hour).In(tc.loc)
+			to := day3.Add(12 * time.Hour).In(tc.loc)
+			nextDay := func(t time.Time) time.Time {
+				return time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, t.Location())
+			}
+			// The server has no response for from, and responds to requests for
+			// each following midnight in tc.loc with the local day containing
+			// it.
+			var options []dstest.Option
+			for requestTime := nextDay(from); requestTime.Before(to); requestTime = nextDay(requestTime) {
+				local := requestTime.In(losAngeles)
+				day := time.Date(local.Year(), local.Month(), local.Day(), 0, 0, 0, 0, losAngeles)
+				options = append(options, withHistoryDay(day, requestTime))
+			}
+			s := dstest.NewServer(options...)
+			defer s.Close()
+			c, err := s.NewClient()
+			require.NoError(t, err)
+
+			history, err := c.History(context.Background(), 34.0219, -118.4814, from, to, nil)
+			var historyErr *darksky.HistoryError
+			require.True(t, errors.As(err, &historyErr))
+			require.Len(t, historyErr.Errs, len(tc.expectedFailedDays))
+			for i, day := range tc.expectedFailedDays {
+				assert.True(t, day.Equal(historyErr.Errs[i].Day), "%s", historyErr.Errs[i].Day)
+				assert.True(t, errors.Is(historyErr.Errs[i].Err, darksky.ErrNotFound))
+			}
+			require.NotNil(t, history)
+			assert.Equal(t, "America/Los_Angeles", history.Timezone)
+			assert.Len(t, history.Hourly, tc.expectedHours)
+			assert.Len(t, history.Daily, tc.expectedDays)
+		})
+	}
+}
+
+func TestClientHistoryEmptyTimeRange(t *testing.T) {
+	c, err := darksky.NewClient(darksky.WithKey("key"))
+	require.NoError(t, err)
+	from := time.Date(2019, 5, 1, 0, 0, 0, 0, time.UTC)
+	for _, to := range []time.Time{from, from.Add(-time.Hour)} {
+		_, err := c.History(context.Background(), 34.0219, -118.4814, from, to, nil)
+		assert.True(t, errors.Is(err, darksky.ErrEmptyTimeRange), to)
+	}
+}
+
+// withHistoryDay returns an option that adds a synthetic time machine response
+// for day to a request for requestTime. Each response also contains the first
+// hour of the following day, to test deduplication.
+func withHistoryDay(day, requestTime time.Time) dstest.Option {
+	nextDay := time.Date(day.Year(), day.Month(), day.Day()+1, 0, 0, 0, 0, day.Location())
+	var hourly []string
+	for hour := day; !hour.After(nextDay); hour = hour.Add(time.Hour) {
+		hourly = append(hourly, fmt.Sprintf(`{"time":%d,"temperature":%d}`, hour.Unix(), hour.Hour()))
+	}
+	forecastStr := fmt.Sprintf(`{"latitude":34.0219,"longitude":-118.4814,"timezone":"America/Los_Angeles","offset":-7,`+
+		`"hourly":{"data":[%s]},"daily":{"data":[{"time":%d}]}}`, strings.Join(hourly, ","), day.Unix())
+	return dstest.WithForecast(dstest.Request{
+		Latitude:  34.0219,
+		Longitude: -118.4814,
+		Time:      darksky.Time{Time: requestTime.UTC()},
+		Extend:    darksky.DefaultExtend,
+		Lang:      darksky.DefaultLang,
+		Units:     darksky.DefaultUnits,
+	}, forecastStr)
+}
@@ -0,0 +1,156 @@
+package darksky
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/twpayne/go-darksky/icons"
+)
+
+// ErrUnknownIcon is returned when a string or code does not correspond to any
+// Icon.
+var ErrUnknownIcon = errors.New("darksky: unknown icon")
+
+// An iconMapping maps an Icon to other representations.
+type iconMapping struct {
+	emoji             string
+	wmoCode           int
+	weatherIconsClass string
+}
+
+// iconMappings are the mappings of each Icon.
+var iconMappings = map[Icon]iconMapping{
+	IconClearDay:          {emoji: "☀️", wmoCode: 0, weatherIconsClass: "wi-day-sunny"},
+	IconClearNight:        {emoji: "🌙", wmoCode: 0, weatherIconsClass: "wi-night-clear"},
+	IconCloudy:            {emoji: "☁️", wmoCode: 3, weatherIconsClass: "wi-cloudy"},
+	IconFog:               {emoji: "🌫️", wmoCode: 45, weatherIconsClass: "wi-fog"},
+	IconPartlyCloudyDay:   {emoji: "⛅", wmoCode: 2, weatherIconsClass: "wi-day-cloudy"},
+	IconPartlyCloudyNight: {emoji: "⛅", wmoCode: 2, weatherIconsClass: "wi-night-alt-cloudy"},
+	IconRain:              {emoji: "🌧️", wmoCode: 63, weatherIconsClass: "wi-rain"},
+	IconSleet:             {emoji: "🌨️", wmoCode: 79, weatherIconsClass: "wi-sleet"},
+	IconSnow:              {emoji: "❄️", wmoCode: 73, weatherIconsClass: "wi-snow"},
+	IconWind:              {emoji: "🌬️", wmoCode: 18, weatherIconsClass: "wi-strong-wind"},
+}
+
+// wmoCodeRanges map ranges of WMO 4677 present weather codes to Icons. Codes
+// that do not describe the present weather at the station, for example those
+// describing the preceding hour, are not mapped.
+var wmoCodeRanges = []struct {
+	min, max int
+	icon     Icon
+}{
+	{0, 1, IconClearDay},        // Sky clear or clouds dissolving.
+	{2, 2, IconPartlyCloudyDay}, // State of sky unchanged.
+	{3, 3, IconCloudy},          // Clouds forming.
+	{4, 5, IconFog},             // Smoke or haze.
+	{10, 12, IconFog},           // Mist or shallow fog.
+	{18, 18, IconWind},          // Squalls.
+	{30, 35, IconWind},          // Duststorm or sandstorm.
+	{36, 39, IconSnow},          // Drifting or blowing snow.
+	{40, 49, IconFog},           // Fog.
+	{50, 55, IconRain},          // Drizzle.
+	{56, 57, IconSleet},         // Freezing drizzle.
+	{58, 65, IconRain},          // Rain.
+	{66, 69, IconSleet},         // Freezing rain, or rain and snow.
+	{70, 78, IconSnow},          // Snow.
+	{79, 79, IconSleet},         // Ice pellets.
+	{80, 82, IconRain},          // Rain showers.
+	{83, 84, IconSleet},         // Rain and snow showers.
+	{85, 86, IconSnow},          // Snow showers.
+	{87, 90, IconSleet},         // Snow pellets, small hail, or hail.
+	{91, 99, IconRain},          // Thunderstorms.
+}
+
+// ParseIcon parses s as an Icon.
+func ParseIcon(s string) (Icon, error) {
+	for _, icon := range Icons {
+		if s == string(icon) {
+			return icon, nil
+		}
+	}
+	return "", fmt.Errorf("%w: %q", ErrUnknownIcon, s)
+}
+
+// IconFromEmoji returns the Icon of emoji. Emoji are matched with or without
+// variation selectors. Emoji that do not distinguish day and night return the
+// day Icon.
+func IconFromEmoji(emoji string) (Icon, error) {
+	normalizedEmoji := stripVariationSelectors(emoji)
+	for _, icon := range Icons {
+		if stripVariationSelectors(iconMappings[icon].emoji) == normalizedEmoji {
+			return icon, nil
+		}
+	}
+	return "", fmt.Errorf("%w: emoji %q", ErrUnknownIcon, emoji)
+}
+
+// IconFromWMOCode returns the Icon of WMO 4677 present weather code code.
+// Codes do not distinguish day and night so the day Icon is returned.
+func IconFromWMOCode(code int) (Icon, error) {
+	for _, r := range wmoCodeRanges {
+		if r.min <= code && code <= r.max {
+			return r.icon, nil
+		}
+	}
+	return "", fmt.Errorf("%w: WMO code %d", ErrUnknownIcon, code)
+}
+
+// IconFromWeatherIconsClass returns the Icon of Weather Icons CSS class name
+// class, see https://erikflowers.github.io/weather-icons/. Weather Icons'
+// Forecast.io class names, for example "wi-forecast-io-clear-day", are also
+// accepted.
+func IconFromWeatherIconsClass(class string) (Icon, error) {
+	if s := strings.TrimPrefix(class, "wi-forecast-io-"); s != class {
+		if icon, err := ParseIcon(s); err == nil {
+			return icon, nil
+		}
+	}
+	for _, icon := range Icons {
+		if iconMappings[icon].weatherIconsClass == class {
+			return icon, nil
+		}
+	}
+	return "", fmt.Errorf("%w: Weather Icons class %q", ErrUnknownIcon, class)
+}
+
+// Emoji returns the emoji of i, or the empty string if i is unknown.
+func (i Icon) Emoji() string {
+	return iconMappings[i].emoji
+}
+
+// IsNight returns whether i is a night icon.
+func (i Icon) IsNight() bool {
+	return i == IconClearNight || i == IconPartlyCloudyNight
+}
+
+// IsPrecipitation returns whether i is a precipitation icon.
+func (i Icon) IsPrecipitation() bool {
+	return i == IconRain || i == IconSleet || i == IconSnow
+}
+
+// SVG returns the SVG image of i, or nil if i is unknown. The images are
+// provided by package github.com/twpayne/go-darksky/icons.
+func (i Icon) SVG() []byte {
+	svg, _ := icons.SVG(string(i))
+	return svg
+}
+
+// WMOCode returns the WMO 4677 present weather code of i. It returns false if
+// i is unknown.
+func (i Icon) WMOCode() (int, bool) {
+	m, ok := iconMappings[i]
+	return m.wmoCode, ok
+}
+
+// WeatherIconsClass returns the Weather Icons CSS class name of i, or the empty
+// string if i is unknown.
+func (i Icon) WeatherIconsClass() string {
+	return iconMappings[i].weatherIconsClass
+}
+
+// stripVariationSelectors returns s with all emoji and text variation
+// selectors removed.
+func stripVariationSelectors(s string) string {
+	return strings.NewReplacer("\ufe0e", "", "\ufe0f", "").Replace(s)
+}
@@ -0,0 +1,162 @@
+package darksky_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/twpayne/go-darksky"
+)
+
+func TestIconMappings(t *testing.T) {
+	for _, icon := range darksky.Icons {
+		t.Run(string(icon), func(t *testing.T) {
+			dayIcon := icon
+			switch icon {
+			case darksky.IconPartlyCloudyNight:
+				dayIcon = darksky.IconPartlyCloudyDay
+			case darksky.IconClearNight:
+				dayIcon = darksky.IconClearDay
+			}
+
+			actual, err := darksky.ParseIcon(string(icon))
+			require.NoError(t, err)
+			assert.Equal(t, icon, actual)
+
+			emoji := icon.Emoji()
+			require.NotEmpty(t, emoji)
+			actual, err = darksky.IconFromEmoji(emoji)
+			require.NoError(t, err)
+			if icon == darksky.IconClearNight {
+				assert.Equal(t, icon, actual)
+			} else {
+				assert.Equal(t, dayIcon, actual)
+			}
+
+			code, ok := icon.WMOCode()
+			require.True(t, ok)
+			actual, err = darksky.IconFromWMOCode(code)
+			require.NoError(t, err)
+			assert.Equal(t, dayIcon, actual)
+
+			class := icon.WeatherIconsClass()
+			require.NotEmpty(t, class)
+			actual, err = darksky.IconFromWeatherIconsClass(class)
+			require.NoError(t, err)
+			assert.Equal(t, icon, actual)
+			actual, err = darksky.IconFromWeatherIconsClass("wi-forecast-io-" + string(icon))
+			require.NoError(t, err)
+			assert.Equal(t, icon, actual)
+		})
+	}
+}
+
+func TestParseIcon(t *testing.T) {
+	for _, s := range []string{"", "Rain", "thunderstorm", "hail"} {
+		_, err := darksky.ParseIcon(s)
+		assert.True(t, errors.Is(err, darksky.ErrUnknownIcon), s)
+	}
+}
+
+func TestIconFromEmoji(t *testing.T) {
+	for _, tc := range []struct {
+		emoji       string
+		expected    darksky.Icon
+		expectedErr bool
+	}{
+		{emoji: "☀️", expected: darksky.IconClearDay},
+		{emoji: "☀", expected: darksky.IconClearDay},
+		{emoji: "☁︎", expected: darksky.IconCloudy},
+		{emoji: "🌙", expected: darksky.IconClearNight},
+		{emoji: "🌧", expected: darksky.IconRain},
+		{emoji: "🌈", expectedErr: true},
+		{emoji: "", expectedErr: true},
+	} {
+		actual, err := darksky.IconFromEmoji(tc.emoji)
+		if tc.expectedErr {
+			assert.True(t, errors.Is(err, darksky.ErrUnknownIcon), tc.emoji)
+		} else {
+			assert.NoError(t, err, tc.emoji)
+			assert.Equal(t, tc.expected, actual, tc.emoji)
+		}
+	}
+}
+
+func TestIconFromWMOCode(t *testing.T) {
+	for _, tc := range []struct {
+		code        int
+		expected    darksky.Icon
+		expectedErr bool
+	}{
+		{code: 1, expected: darksky.IconClearDay},
+		{code: 10, expected: darksky.IconFog},
+		{code: 38, expected: darksky.IconSnow},
+		{code: 51, expected: darksky.IconRain},
+		{code: 56, expected: darksky.IconSleet},
+		{code: 61, expected: darksky.IconRain},
+		{code: 67, expected: darksky.IconSleet},
+		{code: 75, expected: darksky.IconSnow},
+		{code: 81, expected: darksky.IconRain},
+		{code: 86, expected: darksky.IconSnow},
+		{code: 89, expected: darksky.IconSleet},
+		{code: 95, expected: darksky.IconRain},
+		{code: 21, expectedErr: true},
+		{code: -1, expectedErr: true},
+		{code: 100, expectedErr: true},
+	} {
+		actual, err := darksky.IconFromWMOCode(tc.code)
+		if tc.expectedErr {
+			assert.True(t, errors.Is(err, darksky.ErrUnknownIcon), tc.code)
+		} else {
+			assert.NoError(t, err, tc.code)
+			assert.Equal(t, tc.expected, actual, tc.code)
+		}
+	}
+}
+
+func TestIconFromWeatherIconsClass(t *testing.T) {
+	for _, class := range []string{"", "wi-tornado", "wi-forecast-io-tornado", "day-sunny"} {
+		_, err := darksky.IconFromWeatherIconsClass(class)
+		assert.True(t, errors.Is(err, darksky.ErrUnknownIcon), class)
+	}
+}
+
+func TestIconClassification(t *testing.T) {
+	for _, tc := range []struct {
+		icon                    darksky.Icon
+		expectedIsNight         bool
+		expectedIsPrecipitation bool
+	}{
+		{icon: darksky.IconClearDay},
+		{icon: darksky.IconClearNight, expectedIsNight: true},
+		{icon: darksky.IconCloudy},
+		{icon: darksky.IconFog},
+		{icon: darksky.IconPartlyCloudyDay},
+		{icon: darksky.IconPartlyCloudyNight, expectedIsNight: true},
+		{icon: darksky.IconRain, expectedIsPrecipitation: true},
+		{icon: darksky.IconSleet, expectedIsPrecipitation: true},
+		{icon: darksky.IconSnow, expectedIsPrecipitation: true},
+		{icon: darksky.IconWind},
+	} {
+		assert.Equal(t, tc.expectedIsNight, tc.icon.IsNight(), tc.icon)
+		assert.Equal(t, tc.expectedIsPrecipitation, tc.icon.IsPrecipitation(), tc.icon)
+	}
+	assert.Len(t, darksky.Icons, 10)
+}
+
+func TestIconSVG(t *testing.T) {
+	for _, icon := range darksky.Icons {
+		assert.Contains(t, string(icon.SVG()), "<svg ", icon)
+	}
+}
+
+func TestIconUnknown(t *testing.T) {
+	icon := darksky.Icon("tornado")
+	assert.Nil(t, icon.SVG())
+	assert.Equal(t, "", icon.Emoji())
+	assert.Equal(t, "", icon.WeatherIconsClass())
+	_, ok := icon.WMOCode()
+	assert.False(t, ok)
+}
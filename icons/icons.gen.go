@@ -0,0 +1,73 @@
+// Automatically generated file. DO NOT EDIT.
+
+package icons
+
+var svgs = map[string]string{
+	"clear-day": `<svg xmlns="http://www.w3.org/2000/svg" viewBox="0 0 64 64" width="64" height="64">
+<title>clear-day</title>
+<path d="M32 4v8M32 52v8M4 32h8M52 32h8M12.2 12.2l5.7 5.7M46.1 46.1l5.7 5.7M12.2 51.8l5.7-5.7M46.1 17.9l5.7-5.7" stroke="#f5a623" stroke-width="4" stroke-linecap="round"/>
+<circle cx="32" cy="32" r="12" fill="#f5a623"/>
+</svg>
+`,
+	"clear-night": `<svg xmlns="http://www.w3.org/2000/svg" viewBox="0 0 64 64" width="64" height="64">
+<title>clear-night</title>
+<path d="M40 8a24 24 0 1 0 16 40A20 20 0 0 1 40 8z" fill="#f0c419"/>
+</svg>
+`,
+	"cloudy": `<svg xmlns="http://www.w3.org/2000/svg" viewBox="0 0 64 64" width="64" height="64">
+<title>cloudy</title>
+<path d="M18 48h30a11 11 0 0 0 1-22 15 15 0 0 0-29-2 12 12 0 0 0-2 24z" fill="#9aa5b1"/>
+</svg>
+`,
+	"fog": `<svg xmlns="http://www.w3.org/2000/svg" viewBox="0 0 64 64" width="64" height="64">
+<title>fog</title>
+<path d="M18 48h30a11 11 0 0 0 1-22 15 15 0 0 0-29-2 12 12 0 0 0-2 24z" fill="#9aa5b1" transform="translate(0 -10)"/>
+<path d="M12 46h40M16 54h32" stroke="#9aa5b1" stroke-width="4" stroke-linecap="round"/>
+</svg>
+`,
+	"partly-cloudy-day": `<svg xmlns="http://www.w3.org/2000/svg" viewBox="0 0 64 64" width="64" height="64">
+<title>partly-cloudy-day</title>
+<g transform="translate(20 -8) scale(0.6)">
+<path d="M32 4v8M32 52v8M4 32h8M52 32h8M12.2 12.2l5.7 5.7M46.1 46.1l5.7 5.7M12.2 51.8l5.7-5.7M46.1 17.9l5.7-5.7" stroke="#f5a623" stroke-width="4" stroke-linecap="round"/>
+<circle cx="32" cy="32" r="12" fill="#f5a623"/>
+</g>
+<path d="M18 48h30a11 11 0 0 0 1-22 15 15 0 0 0-29-2 12 12 0 0 0-2 24z" fill="#9aa5b1"/>
+</svg>
+`,
+	"partly-cloudy-night": `<svg xmlns="http://www.w3.org/2000/svg" viewBox="0 0 64 64" width="64" height="64">
+<title>partly-cloudy-night</title>
+<g transform="translate(22 -4) scale(0.6)">
+<path d="M40 8a24 24 0 1 0 16 40A20 20 0 0 1 40 8z" fill="#f0c419"/>
+</g>
+<path d="M18 48h30a11 11 0 0 0 1-22 15 15 0 0 0-29-2 12 12 0 0 0-2 24z" fill="#9aa5b1"/>
+</svg>
+`,
+	"rain": `<svg xmlns="http://www.w3.org/2000/svg" viewBox="0 0 64 64" width="64" height="64">
+<title>rain</title>
+<path d="M18 48h30a11 11 0 0 0 1-22 15 15 0 0 0-29-2 12 12 0 0 0-2 24z" fill="#9aa5b1" transform="translate(0 -10)"/>
+<path d="M24 44l-3 8M34 44l-3 8M44 44l-3 8" stroke="#4a90e2" stroke-width="3" stroke-linecap="round"/>
+</svg>
+`,
+	"sleet": `<svg xmlns="http://www.w3.org/2000/svg" viewBox="0 0 64 64" width="64" height="64">
+<title>sleet</title>
+<path d="M18 48h30a11 11 0 0 0 1-22 15 15 0 0 0-29-2 12 12 0 0 0-2 24z" fill="#9aa5b1" transform="translate(0 -10)"/>
+<path d="M24 44l-3 8M44 44l-3 8" stroke="#4a90e2" stroke-width="3" stroke-linecap="round"/>
+<circle cx="32" cy="48" r="2.5" fill="#8fc1e9"/>
+</svg>
+`,
+	"snow": `<svg xmlns="http://www.w3.org/2000/svg" viewBox="0 0 64 64" width="64" height="64">
+<title>snow</title>
+<path d="M18 48h30a11 11 0 0 0 1-22 15 15 0 0 0-29-2 12 12 0 0 0-2 24z" fill="#9aa5b1" transform="translate(0 -10)"/>
+<circle cx="22" cy="46" r="2.5" fill="#8fc1e9"/>
+<circle cx="32" cy="52" r="2.5" fill="#8fc1e9"/>
+<circle cx="42" cy="46" r="2.5" fill="#8fc1e9"/>
+<circle cx="27" cy="58" r="2.5" fill="#8fc1e9"/>
+<circle cx="37" cy="58" r="2.5" fill="#8fc1e9"/>
+</svg>
+`,
+	"wind": `<svg xmlns="http://www.w3.org/2000/svg" viewBox="0 0 64 64" width="64" height="64">
+<title>wind</title>
+<path d="M6 24h34a7 7 0 1 0-7-7M6 34h46a7 7 0 1 1-7 7M6 44h22" fill="none" stroke="#9aa5b1" stroke-width="4" stroke-linecap="round"/>
+</svg>
+`,
+}
@@ -0,0 +1,73 @@
+// Package icons contains an SVG image for each Dark Sky icon.
+//
+// The images are original drawings distributed under the same MIT license as
+// the rest of this module. Their sources are in the svg directory and are
+// compiled into this package by running make icons.
+package icons
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+)
+
+// CacheControl is the value of the Cache-Control header set by Handler.
+const CacheControl = "public, max-age=86400"
+
+// Names returns the names of all icons, sorted.
+func Names() []string {
+	names := make([]string, 0, len(svgs))
+	for name := range svgs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// SVG returns the SVG image of the icon with name name, for example
+// "clear-day". It returns false if there is no such icon.
+func SVG(name string) ([]byte, bool) {
+	svg, ok := svgs[name]
+	if !ok {
+		return nil, false
+	}
+	return []byte(svg), true
+}
+
+// Handler returns an http.Handler that serves the SVG image of each icon at
+// /<name>.svg, for example /clear-day.svg. Responses include Cache-Control
+// and ETag headers, and conditional requests are supported. Use
+// http.StripPrefix to serve the icons under a different path.
+func Handler() http.Handler {
+	etags := make(map[string]string, len(svgs))
+	for name, svg := range svgs {
+		sum := sha256.Sum256([]byte(svg))
+		etags[name] = `"` + hex.EncodeToString(sum[:16]) + `"`
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet && r.Method != http.MethodHead {
+			w.Header().Set("Allow", "GET, HEAD")
+			http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+			return
+		}
+		name := strings.TrimPrefix(r.URL.Path, "/")
+		if !strings.HasSuffix(name, ".svg") {
+			http.NotFound(w, r)
+			return
+		}
+		name = strings.TrimSuffix(name, ".svg")
+		svg, ok := svgs[name]
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		w.Header().Set("Cache-Control", CacheControl)
+		w.Header().Set("Content-Type", "image/svg+xml")
+		w.Header().Set("ETag", etags[name])
+		http.ServeContent(w, r, name+".svg", time.Time{}, bytes.NewReader([]byte(svg)))
+	})
+}
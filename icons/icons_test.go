@@ -0,0 +1,127 @@
+package icons
+
+import (
+	"encoding/xml"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// iconConstants returns the values of all constants of type Icon declared in
+// filename.
+func iconConstants(t *testing.T, filename string) []string {
+	f, err := parser.ParseFile(token.NewFileSet(), filename, nil, 0)
+	require.NoError(t, err)
+	var values []string
+	for _, decl := range f.Decls {
+		genDecl, ok := decl.(*ast.GenDecl)
+		if !ok || genDecl.Tok != token.CONST {
+			continue
+		}
+		for _, spec := range genDecl.Specs {
+			valueSpec := spec.(*ast.ValueSpec)
+			if ident, ok := valueSpec.Type.(*ast.Ident); !ok || ident.Name != "Icon" {
+				continue
+			}
+			for _, value := range valueSpec.Values {
+				basicLit, ok := value.(*ast.BasicLit)
+				require.True(t, ok)
+				s, err := strconv.Unquote(basicLit.Value)
+				require.NoError(t, err)
+				values = append(values, s)
+			}
+		}
+	}
+	return values
+}
+
+func TestEveryIconHasSVG(t *testing.T) {
+	names := iconConstants(t, "../forecast.go")
+	require.NotEmpty(t, names)
+	for _, name := range names {
+		svg, ok := SVG(name)
+		if assert.True(t, ok, name) {
+			var root struct {
+				XMLName xml.Name
+				Title   string `xml:"title"`
+			}
+			require.NoError(t, xml.Unmarshal(svg, &root), name)
+			assert.Equal(t, "svg", root.XMLName.Local, name)
+			assert.Equal(t, name, root.Title)
+		}
+	}
+	assert.Equal(t, len(names), len(Names()))
+}
+
+func TestGeneratedSVGsUpToDate(t *testing.T) {
+	filenames, err := filepath.Glob("svg/*.svg")
+	require.NoError(t, err)
+	require.Len(t, filenames, len(svgs))
+	for _, filename := range filenames {
+		expected, err := ioutil.ReadFile(filename)
+		require.NoError(t, err)
+		actual, ok := SVG(strings.TrimSuffix(filepath.Base(filename), ".svg"))
+		assert.True(t, ok, filename)
+		assert.Equal(t, string(expected), string(actual), "%s: run make icons", filename)
+	}
+}
+
+func TestSVGUnknown(t *testing.T) {
+	svg, ok := SVG("tornado")
+	assert.False(t, ok)
+	assert.Nil(t, svg)
+}
+
+func TestHandler(t *testing.T) {
+	s := httptest.NewServer(http.StripPrefix("/icons", Handler()))
+	defer s.Close()
+
+	resp, err := s.Client().Get(s.URL + "/icons/rain.svg")
+	require.NoError(t, err)
+	body, err := ioutil.ReadAll(resp.Body)
+	require.NoError(t, err)
+	require.NoError(t, resp.Body.Close())
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, "image/svg+xml", resp.Header.Get("Content-Type"))
+	assert.Equal(t, CacheControl, resp.Header.Get("Cache-Control"))
+	expected, _ := SVG("rain")
+	assert.Equal(t, expected, body)
+	etag := resp.Header.Get("ETag")
+	assert.NotEmpty(t, etag)
+
+	req, err := http.NewRequest(http.MethodGet, s.URL+"/icons/rain.svg", nil)
+	require.NoError(t, err)
+	req.Header.Set("If-None-Match", etag)
+	resp, err = s.Client().Do(req)
+	require.NoError(t, err)
+	require.NoError(t, resp.Body.Close())
+	assert.Equal(t, http.StatusNotModified, resp.StatusCode)
+
+	for _, tc := range []struct {
+		method             string
+		path               string
+		expectedStatusCode int
+	}{
+		{method: http.MethodHead, path: "/icons/snow.svg", expectedStatusCode: http.StatusOK},
+		{method: http.MethodGet, path: "/icons/snow", expectedStatusCode: http.StatusNotFound},
+		{method: http.MethodGet, path: "/icons/tornado.svg", expectedStatusCode: http.StatusNotFound},
+		{method: http.MethodPost, path: "/icons/snow.svg", expectedStatusCode: http.StatusMethodNotAllowed},
+	} {
+		req, err := http.NewRequest(tc.method, s.URL+tc.path, nil)
+		require.NoError(t, err)
+		resp, err := s.Client().Do(req)
+		require.NoError(t, err)
+		require.NoError(t, resp.Body.Close())
+		assert.Equal(t, tc.expectedStatusCode, resp.StatusCode, "%s %s", tc.method, tc.path)
+	}
+}
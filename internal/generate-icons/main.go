@@ -0,0 +1,78 @@
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"go/format"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"text/template"
+)
+
+var (
+	dir        = flag.String("dir", "icons/svg", "directory")
+	packageVal = flag.String("package", "icons", "package")
+)
+
+var outputTemplate = template.Must(template.New("output").Parse(`// Automatically generated file. DO NOT EDIT.
+
+package {{ .Package }}
+
+var svgs = map[string]string{
+{{- range .SVGs }}
+	"{{ .Name }}": {{ .BackquotedSVG }},
+{{- end }}
+}
+`))
+
+type svg struct {
+	Name          string
+	BackquotedSVG string
+}
+
+func run() error {
+	flag.Parse()
+	filenames, err := filepath.Glob(filepath.Join(*dir, "*.svg"))
+	if err != nil {
+		return err
+	}
+	sort.Strings(filenames)
+	svgs := make([]svg, 0, len(filenames))
+	for _, filename := range filenames {
+		data, err := ioutil.ReadFile(filename)
+		if err != nil {
+			return err
+		}
+		if bytes.IndexByte(data, '`') != -1 {
+			return fmt.Errorf("%s: contains a backquote", filename)
+		}
+		svgs = append(svgs, svg{
+			Name:          strings.TrimSuffix(filepath.Base(filename), ".svg"),
+			BackquotedSVG: fmt.Sprintf("`%s`", data),
+		})
+	}
+	b := &bytes.Buffer{}
+	if err := outputTemplate.Execute(b, map[string]interface{}{
+		"Package": *packageVal,
+		"SVGs":    svgs,
+	}); err != nil {
+		return err
+	}
+	source, err := format.Source(b.Bytes())
+	if err != nil {
+		return err
+	}
+	_, err = os.Stdout.Write(source)
+	return err
+}
+
+func main() {
+	if err := run(); err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+}
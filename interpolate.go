@@ -0,0 +1,88 @@
+package darksky
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"time"
+)
+
+// ErrOutsideData is returned by Hourly.Interpolate when the time is outside
+// the times of the data points.
+var ErrOutsideData = errors.New("darksky: time outside data")
+
+// Interpolate returns the data at t, interpolated from the data points either
+// side of t. Numeric fields are interpolated linearly, except for WindBearing
+// which is interpolated along the shortest arc. Other fields are taken from
+// the nearest data point. A numeric field is recorded as present, see
+// HourlyData.Optional, if it is present in both data points. Data points with
+// nil Times are ignored. If t is outside the times of the data points then an
+// error wrapping ErrOutsideData is returned.
+func (h *Hourly) Interpolate(t time.Time) (*HourlyData, error) {
+	var before, after *HourlyData
+	if h != nil {
+		for _, d := range h.Data {
+			if d == nil || d.Time == nil {
+				continue
+			}
+			if !d.Time.After(t) {
+				before = d
+			}
+			if !d.Time.Before(t) {
+				after = d
+				break
+			}
+		}
+	}
+	if before == nil || after == nil {
+		return nil, fmt.Errorf("%w: %s", ErrOutsideData, t.Format(time.RFC3339))
+	}
+
+	var x float64
+	if span := after.Time.Sub(before.Time.Time); span > 0 {
+		x = float64(t.Sub(before.Time.Time)) / float64(span)
+	}
+	lerp := func(a, b float64) float64 {
+		return a + x*(b-a)
+	}
+	nearest := before
+	if x >= 0.5 {
+		nearest = after
+	}
+
+	return &HourlyData{
+		ApparentTemperature:  lerp(before.ApparentTemperature, after.ApparentTemperature),
+		CloudCover:           lerp(before.CloudCover, after.CloudCover),
+		DewPoint:             lerp(before.DewPoint, after.DewPoint),
+		Humidity:             lerp(before.Humidity, after.Humidity),
+		Icon:                 nearest.Icon,
+		Ozone:                lerp(before.Ozone, after.Ozone),
+		PrecipAccumulation:   lerp(before.PrecipAccumulation, after.PrecipAccumulation),
+		PrecipIntensity:      lerp(before.PrecipIntensity, after.PrecipIntensity),
+		PrecipIntensityError: lerp(before.PrecipIntensityError, after.PrecipIntensityError),
+		PrecipProbability:    lerp(before.PrecipProbability, after.PrecipProbability),
+		PrecipType:           nearest.PrecipType,
+		Pressure:             lerp(before.Pressure, after.Pressure),
+		Summary:              nearest.Summary,
+		Temperature:          lerp(before.Temperature, after.Temperature),
+		Time:                 &Time{Time: t.In(before.Time.Location())},
+		UVIndex:              lerp(before.UVIndex, after.UVIndex),
+		Visibility:           lerp(before.Visibility, after.Visibility),
+		WindBearing:          interpolateBearing(before.WindBearing, after.WindBearing, x),
+		WindGust:             lerp(before.WindGust, after.WindGust),
+		WindGustTime:         nearest.WindGustTime,
+		WindSpeed:            lerp(before.WindSpeed, after.WindSpeed),
+		present:              before.present & after.present,
+	}, nil
+}
+
+// interpolateBearing interpolates between bearings a and b, in degrees, along
+// the shortest arc. The result is in the range [0, 360).
+func interpolateBearing(a, b, x float64) float64 {
+	delta := math.Mod(math.Mod(b-a, 360)+540, 360) - 180
+	bearing := math.Mod(a+x*delta, 360)
+	if bearing < 0 {
+		bearing += 360
+	}
+	return bearing
+}
@@ -0,0 +1,146 @@
+package darksky
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHourlyInterpolate(t *testing.T) {
+	t0 := time.Unix(1556668800, 0)
+	t1 := t0.Add(time.Hour)
+	h0 := &HourlyData{
+		Icon:        IconClearDay,
+		Summary:     "Clear",
+		Temperature: 10,
+		Time:        &Time{Time: t0},
+		WindBearing: 350,
+		WindSpeed:   2,
+	}
+	h1 := &HourlyData{
+		Icon:        IconRain,
+		PrecipType:  PrecipTypeRain,
+		Summary:     "Rain",
+		Temperature: 14,
+		Time:        &Time{Time: t1},
+		WindBearing: 30,
+		WindSpeed:   6,
+	}
+	hourly := &Hourly{
+		Data: []*HourlyData{nil, h0, {}, h1},
+	}
+
+	for _, tc := range []struct {
+		name     string
+		t        time.Time
+		expected *HourlyData
+	}{
+		{
+			name: "start",
+			t:    t0,
+			expected: &HourlyData{
+				Icon:        IconClearDay,
+				Summary:     "Clear",
+				Temperature: 10,
+				Time:        &Time{Time: t0},
+				WindBearing: 350,
+				WindSpeed:   2,
+			},
+		},
+		{
+			name: "quarter",
+			t:    t0.Add(15 * time.Minute),
+			expected: &HourlyData{
+				Icon:        IconClearDay,
+				Summary:     "Clear",
+				Temperature: 11,
+				Time:        &Time{Time: t0.Add(15 * time.Minute)},
+				WindBearing: 0,
+				WindSpeed:   3,
+			},
+		},
+		{
+			name: "three_quarters",
+			t:    t0.Add(45 * time.Minute),
+			expected: &HourlyData{
+				Icon:        IconRain,
+				PrecipType:  PrecipTypeRain,
+				Summary:     "Rain",
+				Temperature: 13,
+				Time:        &Time{Time: t0.Add(45 * time.Minute)},
+				WindBearing: 20,
+				WindSpeed:   5,
+			},
+		},
+		{
+			name: "end",
+			t:    t1,
+			expected: &HourlyData{
+				Icon:        IconRain,
+				PrecipType:  PrecipTypeRain,
+				Summary:     "Rain",
+				Temperature: 14,
+				Time:        &Time{Time: t1},
+				WindBearing: 30,
+				WindSpeed:   6,
+			},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			actual, err := hourly.Interpolate(tc.t)
+			require.NoError(t, err)
+			assert.InDelta(t, tc.expected.WindBearing, actual.WindBearing, 1e-9)
+			actual.WindBearing = tc.expected.WindBearing
+			assert.Equal(t, tc.expected, actual)
+		})
+	}
+
+	for _, tt := range []time.Time{t0.Add(-time.Nanosecond), t1.Add(time.Nanosecond)} {
+		_, err := hourly.Interpolate(tt)
+		assert.True(t, errors.Is(err, ErrOutsideData))
+	}
+	var nilHourly *Hourly
+	_, err := nilHourly.Interpolate(t0)
+	assert.True(t, errors.Is(err, ErrOutsideData))
+}
+
+func TestHourlyInterpolateAllFields(t *testing.T) {
+	t0 := time.Unix(1556668800, 0)
+	h0 := &HourlyData{Time: &Time{Time: t0}}
+	h1 := &HourlyData{Time: &Time{Time: t0.Add(time.Hour)}}
+	v1 := reflect.ValueOf(h1).Elem()
+	for i := 0; i < v1.NumField(); i++ {
+		if v1.Field(i).Kind() == reflect.Float64 {
+			v1.Field(i).SetFloat(2)
+		}
+	}
+	actual, err := (&Hourly{Data: []*HourlyData{h0, h1}}).Interpolate(t0.Add(30 * time.Minute))
+	require.NoError(t, err)
+	v := reflect.ValueOf(actual).Elem()
+	for i := 0; i < v.NumField(); i++ {
+		if v.Field(i).Kind() == reflect.Float64 {
+			assert.Equal(t, 1.0, v.Field(i).Float(), v.Type().Field(i).Name)
+		}
+	}
+}
+
+func TestInterpolateBearing(t *testing.T) {
+	for _, tc := range []struct {
+		a, b, x  float64
+		expected float64
+	}{
+		{a: 0, b: 90, x: 0.5, expected: 45},
+		{a: 90, b: 0, x: 0.5, expected: 45},
+		{a: 350, b: 10, x: 0.5, expected: 0},
+		{a: 10, b: 350, x: 0.25, expected: 5},
+		{a: 10, b: 350, x: 0.75, expected: 355},
+		{a: 270, b: 90, x: 0, expected: 270},
+		{a: 0, b: 0, x: 0.5, expected: 0},
+	} {
+		assert.InDelta(t, tc.expected, interpolateBearing(tc.a, tc.b, tc.x), 1e-9)
+	}
+}
@@ -0,0 +1,146 @@
+package darksky
+
+import (
+	"golang.org/x/text/language"
+	"golang.org/x/text/message"
+	"golang.org/x/text/message/catalog"
+)
+
+// summaryLangs are the languages in which summaries can be generated locally.
+var summaryLangs = []Lang{
+	LangEN,
+	LangDE,
+	LangES,
+	LangFR,
+	LangNL,
+}
+
+// translations are the translations of generated text, keyed by language and
+// English format string. English text uses the format string itself.
+var translations = map[Lang]map[string]string{
+	LangDE: {
+		"%d%% chance of precipitation":                  "%d%% Niederschlagswahrscheinlichkeit",
+		"%d%% chance of rain":                           "%d%% Regenwahrscheinlichkeit",
+		"%d%% chance of sleet":                          "%d%% Schneeregenwahrscheinlichkeit",
+		"%d%% chance of snow":                           "%d%% Schneewahrscheinlichkeit",
+		"%s for the next hour.":                         "%s während der nächsten Stunde.",
+		"%s starting in %d min, stopping %d min later.": "%s beginnt in %d Min. und hört %d Min. später auf.",
+		"%s starting in %d min.":                        "%s beginnt in %d Min.",
+		"%s stopping in %d min.":                        "%s hört in %d Min. auf.",
+		"Clear":                                         "Klar",
+		"Cloudy":                                        "Bewölkt",
+		"Fog":                                           "Nebel",
+		"No precipitation for the next hour.":           "Kein Niederschlag während der nächsten Stunde.",
+		"Partly cloudy":                                 "Teilweise bewölkt",
+		"Precipitation":                                 "Niederschlag",
+		"Rain":                                          "Regen",
+		"Sleet":                                         "Schneeregen",
+		"Snow":                                          "Schnee",
+		"Windy":                                         "Windig",
+	},
+	LangES: {
+		"%d%% chance of precipitation":                  "%d%% de probabilidad de precipitación",
+		"%d%% chance of rain":                           "%d%% de probabilidad de lluvia",
+		"%d%% chance of sleet":                          "%d%% de probabilidad de aguanieve",
+		"%d%% chance of snow":                           "%d%% de probabilidad de nieve",
+		"%s for the next hour.":                         "%s durante la próxima hora.",
+		"%s starting in %d min, stopping %d min later.": "%s comenzando en %d min, deteniéndose %d min después.",
+		"%s starting in %d min.":                        "%s comenzando en %d min.",
+		"%s stopping in %d min.":                        "%s deteniéndose en %d min.",
+		"Clear":                                         "Despejado",
+		"Cloudy":                                        "Nublado",
+		"Fog":                                           "Niebla",
+		"No precipitation for the next hour.":           "Sin precipitación durante la próxima hora.",
+		"Partly cloudy":                                 "Parcialmente nublado",
+		"Precipitation":                                 "Precipitación",
+		"Rain":                                          "Lluvia",
+		"Sleet":                                         "Aguanieve",
+		"Snow":                                          "Nieve",
+		"Windy":                                         "Ventoso",
+	},
+	LangFR: {
+		"%d%% chance of precipitation":                  "%d %% de risque de précipitations",
+		"%d%% chance of rain":                           "%d %% de risque de pluie",
+		"%d%% chance of sleet":                          "%d %% de risque de grésil",
+		"%d%% chance of snow":                           "%d %% de risque de neige",
+		"%s for the next hour.":                         "%s pendant l'heure à venir.",
+		"%s starting in %d min, stopping %d min later.": "%s commençant dans %d min, s'arrêtant %d min plus tard.",
+		"%s starting in %d min.":                        "%s commençant dans %d min.",
+		"%s stopping in %d min.":                        "%s s'arrêtant dans %d min.",
+		"Clear":                                         "Ciel dégagé",
+		"Cloudy":                                        "Nuageux",
+		"Fog":                                           "Brouillard",
+		"No precipitation for the next hour.":           "Pas de précipitations pendant l'heure à venir.",
+		"Partly cloudy":                                 "Partiellement nuageux",
+		"Precipitation":                                 "Précipitations",
+		"Rain":                                          "Pluie",
+		"Sleet":                                         "Grésil",
+		"Snow":                                          "Neige",
+		"Windy":                                         "Venteux",
+	},
+	LangNL: {
+		"%d%% chance of precipitation":                  "%d%% kans op neerslag",
+		"%d%% chance of rain":                           "%d%% kans op regen",
+		"%d%% chance of sleet":                          "%d%% kans op natte sneeuw",
+		"%d%% chance of snow":                           "%d%% kans op sneeuw",
+		"%s for the next hour.":                         "%s gedurende het komende uur.",
+		"%s starting in %d min, stopping %d min later.": "%s begint over %d min en stopt %d min later.",
+		"%s starting in %d min.":                        "%s begint over %d min.",
+		"%s stopping in %d min.":                        "%s stopt over %d min.",
+		"Clear":                                         "Helder",
+		"Cloudy":                                        "Bewolkt",
+		"Fog":                                           "Mist",
+		"No precipitation for the next hour.":           "Geen neerslag gedurende het komende uur.",
+		"Partly cloudy":                                 "Half bewolkt",
+		"Precipitation":                                 "Neerslag",
+		"Rain":                                          "Regen",
+		"Sleet":                                         "Natte sneeuw",
+		"Snow":                                          "Sneeuw",
+		"Windy":                                         "Winderig",
+	},
+}
+
+var (
+	summaryCatalog = newSummaryCatalog()
+	summaryTags    = newSummaryTags()
+	summaryMatcher = language.NewMatcher(summaryTags)
+)
+
+// SummaryLangs returns the languages in which summaries can be generated
+// locally. Summaries in other languages are generated in English.
+func SummaryLangs() []Lang {
+	return append([]Lang(nil), summaryLangs...)
+}
+
+func newSummaryCatalog() catalog.Catalog {
+	b := catalog.NewBuilder(catalog.Fallback(language.English))
+	for lang, messages := range translations {
+		tag := language.MustParse(string(lang))
+		for key, msg := range messages {
+			if err := b.SetString(tag, key, msg); err != nil {
+				panic(err)
+			}
+		}
+	}
+	return b
+}
+
+func newSummaryTags() []language.Tag {
+	tags := make([]language.Tag, len(summaryLangs))
+	for i, lang := range summaryLangs {
+		tags[i] = language.MustParse(string(lang))
+	}
+	return tags
+}
+
+// newSummaryPrinter returns a new printer for generated text in lang. If lang
+// is not one of summaryLangs then the printer prints English.
+func newSummaryPrinter(lang Lang) *message.Printer {
+	tag := language.English
+	if t, err := language.Parse(string(lang)); err == nil {
+		if _, index, confidence := summaryMatcher.Match(t); confidence != language.No {
+			tag = summaryTags[index]
+		}
+	}
+	return message.NewPrinter(tag, message.Catalog(summaryCatalog))
+}
@@ -0,0 +1,103 @@
+package darksky
+
+import "time"
+
+// A PrecipitationEvent is a period of precipitation in a minutely forecast.
+type PrecipitationEvent struct {
+	Start          *Time      // The time of the first data point with precipitation.
+	End            *Time      // The time of the first following data point without precipitation, or nil if precipitation continues to the end of the data.
+	PeakIntensity  float64    // The maximum precipitation intensity.
+	PeakTime       *Time      // The time of the maximum precipitation intensity.
+	MaxProbability float64    // The maximum precipitation probability.
+	PrecipType     PrecipType // The precipitation type at the peak, or the first reported type.
+}
+
+// precipTypeNouns are the English nouns for precipitation types.
+var precipTypeNouns = map[PrecipType]string{
+	PrecipTypeRain:  "Rain",
+	PrecipTypeSleet: "Sleet",
+	PrecipTypeSnow:  "Snow",
+}
+
+// PrecipitationEvents returns the periods in which the precipitation intensity
+// is greater than threshold, which is in the forecast's units. Data points with
+// nil Times are ignored.
+func (m *Minutely) PrecipitationEvents(threshold float64) []*PrecipitationEvent {
+	if m == nil {
+		return nil
+	}
+	var events []*PrecipitationEvent
+	var event *PrecipitationEvent
+	for _, d := range m.Data {
+		if d == nil || d.Time == nil {
+			continue
+		}
+		if d.PrecipIntensity <= threshold {
+			if event != nil {
+				event.End = d.Time
+				event = nil
+			}
+			continue
+		}
+		if event == nil {
+			event = &PrecipitationEvent{
+				Start: d.Time,
+			}
+			events = append(events, event)
+		}
+		if d.PrecipIntensity > event.PeakIntensity {
+			event.PeakIntensity = d.PrecipIntensity
+			event.PeakTime = d.Time
+			if d.PrecipType != "" {
+				event.PrecipType = d.PrecipType
+			}
+		}
+		if event.PrecipType == "" {
+			event.PrecipType = d.PrecipType
+		}
+		if d.PrecipProbability > event.MaxProbability {
+			event.MaxProbability = d.PrecipProbability
+		}
+	}
+	return events
+}
+
+// PrecipitationSummary returns a summary of the first precipitation event
+// relative to the time of the first data point, for example "Rain starting in
+// 12 min.", in lang. Languages other than SummaryLangs are summarized in
+// English.
+func (m *Minutely) PrecipitationSummary(lang Lang, threshold float64) string {
+	p := newSummaryPrinter(lang)
+	events := m.PrecipitationEvents(threshold)
+	if len(events) == 0 {
+		return p.Sprintf("No precipitation for the next hour.")
+	}
+
+	var now time.Time
+	for _, d := range m.Data {
+		if d != nil && d.Time != nil {
+			now = d.Time.Time
+			break
+		}
+	}
+	minutes := func(from, to time.Time) int {
+		return int(to.Sub(from) / time.Minute)
+	}
+
+	event := events[0]
+	noun, ok := precipTypeNouns[event.PrecipType]
+	if !ok {
+		noun = "Precipitation"
+	}
+	noun = p.Sprintf(noun)
+	switch {
+	case !event.Start.After(now) && event.End == nil:
+		return p.Sprintf("%s for the next hour.", noun)
+	case !event.Start.After(now):
+		return p.Sprintf("%s stopping in %d min.", noun, minutes(now, event.End.Time))
+	case event.End == nil:
+		return p.Sprintf("%s starting in %d min.", noun, minutes(now, event.Start.Time))
+	default:
+		return p.Sprintf("%s starting in %d min, stopping %d min later.", noun, minutes(now, event.Start.Time), minutes(event.Start.Time, event.End.Time))
+	}
+}
@@ -0,0 +1,145 @@
+package darksky_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/twpayne/go-darksky"
+)
+
+func newTestMinutely(t0 time.Time, intensities []float64, precipType darksky.PrecipType) *darksky.Minutely {
+	m := &darksky.Minutely{}
+	for i, intensity := range intensities {
+		d := &darksky.MinutelyData{
+			PrecipIntensity: intensity,
+			Time:            &darksky.Time{Time: t0.Add(time.Duration(i) * time.Minute)},
+		}
+		if intensity > 0 {
+			d.PrecipProbability = intensity / 2
+			d.PrecipType = precipType
+		}
+		m.Data = append(m.Data, d)
+	}
+	return m
+}
+
+func TestMinutelyPrecipitationEvents(t *testing.T) {
+	t0 := time.Unix(1556668800, 0)
+	minute := func(i int) *darksky.Time {
+		return &darksky.Time{Time: t0.Add(time.Duration(i) * time.Minute)}
+	}
+	m := newTestMinutely(t0, []float64{0, 0.01, 0.05, 0.2, 0.1, 0, 0, 0.3, 0.4}, darksky.PrecipTypeRain)
+	m.Data = append(m.Data[:2], append([]*darksky.MinutelyData{nil}, m.Data[2:]...)...)
+
+	assert.Equal(t, []*darksky.PrecipitationEvent{
+		{
+			Start:          minute(1),
+			End:            minute(5),
+			PeakIntensity:  0.2,
+			PeakTime:       minute(3),
+			MaxProbability: 0.1,
+			PrecipType:     darksky.PrecipTypeRain,
+		},
+		{
+			Start:          minute(7),
+			PeakIntensity:  0.4,
+			PeakTime:       minute(8),
+			MaxProbability: 0.2,
+			PrecipType:     darksky.PrecipTypeRain,
+		},
+	}, m.PrecipitationEvents(0))
+
+	events := m.PrecipitationEvents(0.1)
+	assert.Len(t, events, 2)
+	assert.Equal(t, minute(3), events[0].Start)
+	assert.Equal(t, minute(4), events[0].End)
+
+	assert.Nil(t, m.PrecipitationEvents(1))
+	assert.Nil(t, (*darksky.Minutely)(nil).PrecipitationEvents(0))
+}
+
+func TestMinutelyPrecipitationSummary(t *testing.T) {
+	t0 := time.Unix(1556668800, 0)
+	for _, tc := range []struct {
+		name        string
+		intensities []float64
+		precipType  darksky.PrecipType
+		lang        darksky.Lang
+		expected    string
+	}{
+		{
+			name:        "none",
+			intensities: []float64{0, 0, 0},
+			lang:        darksky.LangEN,
+			expected:    "No precipitation for the next hour.",
+		},
+		{
+			name:        "continuing",
+			intensities: []float64{0.1, 0.1, 0.1},
+			precipType:  darksky.PrecipTypeSnow,
+			lang:        darksky.LangEN,
+			expected:    "Snow for the next hour.",
+		},
+		{
+			name:        "stopping",
+			intensities: []float64{0.1, 0.1, 0},
+			precipType:  darksky.PrecipTypeRain,
+			lang:        darksky.LangEN,
+			expected:    "Rain stopping in 2 min.",
+		},
+		{
+			name:        "starting",
+			intensities: []float64{0, 0, 0.1},
+			precipType:  darksky.PrecipTypeSleet,
+			lang:        darksky.LangEN,
+			expected:    "Sleet starting in 2 min.",
+		},
+		{
+			name:        "starting_and_stopping",
+			intensities: []float64{0, 0.1, 0.1, 0.1, 0},
+			lang:        darksky.LangEN,
+			expected:    "Precipitation starting in 1 min, stopping 3 min later.",
+		},
+		{
+			name:        "de",
+			intensities: []float64{0, 0, 0.1},
+			precipType:  darksky.PrecipTypeRain,
+			lang:        darksky.LangDE,
+			expected:    "Regen beginnt in 2 Min.",
+		},
+		{
+			name:        "fr",
+			intensities: []float64{0.1, 0},
+			precipType:  darksky.PrecipTypeSnow,
+			lang:        darksky.LangFR,
+			expected:    "Neige s'arrêtant dans 1 min.",
+		},
+		{
+			name:        "es",
+			intensities: []float64{0},
+			lang:        darksky.LangES,
+			expected:    "Sin precipitación durante la próxima hora.",
+		},
+		{
+			name:        "nl",
+			intensities: []float64{0, 0.1, 0},
+			precipType:  darksky.PrecipTypeRain,
+			lang:        darksky.LangNL,
+			expected:    "Regen begint over 1 min en stopt 1 min later.",
+		},
+		{
+			name:        "unsupported",
+			intensities: []float64{0, 0.1},
+			precipType:  darksky.PrecipTypeRain,
+			lang:        darksky.LangXPigLatin,
+			expected:    "Rain starting in 1 min.",
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			m := newTestMinutely(t0, tc.intensities, tc.precipType)
+			assert.Equal(t, tc.expected, m.PrecipitationSummary(tc.lang, 0))
+		})
+	}
+}
@@ -0,0 +1,279 @@
+package darksky
+
+import (
+	"encoding/json"
+	"reflect"
+)
+
+// An OptionalFloat64 is a float64 that records whether it was present. Value is
+// zero if Valid is false.
+type OptionalFloat64 struct {
+	Value float64
+	Valid bool
+}
+
+// OptionalCurrently contains the numeric fields of a Currently, recording
+// whether each was present in the response.
+type OptionalCurrently struct {
+	ApparentTemperature  OptionalFloat64 `json:"apparentTemperature"`
+	CloudCover           OptionalFloat64 `json:"cloudCover"`
+	DewPoint             OptionalFloat64 `json:"dewPoint"`
+	Humidity             OptionalFloat64 `json:"humidity"`
+	NearestStormBearing  OptionalFloat64 `json:"nearestStormBearing"`
+	NearestStormDistance OptionalFloat64 `json:"nearestStormDistance"`
+	Ozone                OptionalFloat64 `json:"ozone"`
+	PrecipAccumulation   OptionalFloat64 `json:"precipAccumulation"`
+	PrecipIntensity      OptionalFloat64 `json:"precipIntensity"`
+	PrecipIntensityError OptionalFloat64 `json:"precipIntensityError"`
+	PrecipProbability    OptionalFloat64 `json:"precipProbability"`
+	Pressure             OptionalFloat64 `json:"pressure"`
+	Temperature          OptionalFloat64 `json:"temperature"`
+	UVIndex              OptionalFloat64 `json:"uvIndex"`
+	Visibility           OptionalFloat64 `json:"visibility"`
+	WindBearing          OptionalFloat64 `json:"windBearing"`
+	WindGust             OptionalFloat64 `json:"windGust"`
+	WindSpeed            OptionalFloat64 `json:"windSpeed"`
+}
+
+// OptionalDailyData contains the numeric fields of a DailyData, recording
+// whether each was present in the response.
+type OptionalDailyData struct {
+	ApparentTemperatureHigh OptionalFloat64 `json:"apparentTemperatureHigh"`
+	ApparentTemperatureLow  OptionalFloat64 `json:"apparentTemperatureLow"`
+	ApparentTemperatureMax  OptionalFloat64 `json:"apparentTemperatureMax"`
+	ApparentTemperatureMin  OptionalFloat64 `json:"apparentTemperatureMin"`
+	CloudCover              OptionalFloat64 `json:"cloudCover"`
+	DewPoint                OptionalFloat64 `json:"dewPoint"`
+	Humidity                OptionalFloat64 `json:"humidity"`
+	MoonPhase               OptionalFloat64 `json:"moonPhase"`
+	Ozone                   OptionalFloat64 `json:"ozone"`
+	PrecipAccumulation      OptionalFloat64 `json:"precipAccumulation"`
+	PrecipIntensity         OptionalFloat64 `json:"precipIntensity"`
+	PrecipIntensityError    OptionalFloat64 `json:"precipIntensityError"`
+	PrecipIntensityMax      OptionalFloat64 `json:"precipIntensityMax"`
+	PrecipProbability       OptionalFloat64 `json:"precipProbability"`
+	Pressure                OptionalFloat64 `json:"pressure"`
+	TemperatureHigh         OptionalFloat64 `json:"temperatureHigh"`
+	TemperatureLow          OptionalFloat64 `json:"temperatureLow"`
+	TemperatureMax          OptionalFloat64 `json:"temperatureMax"`
+	TemperatureMin          OptionalFloat64 `json:"temperatureMin"`
+	UVIndex                 OptionalFloat64 `json:"uvIndex"`
+	Visibility              OptionalFloat64 `json:"visibility"`
+	WindBearing             OptionalFloat64 `json:"windBearing"`
+	WindGust                OptionalFloat64 `json:"windGust"`
+	WindSpeed               OptionalFloat64 `json:"windSpeed"`
+}
+
+// OptionalHourlyData contains the numeric fields of an HourlyData, recording
+// whether each was present in the response.
+type OptionalHourlyData struct {
+	ApparentTemperature  OptionalFloat64 `json:"apparentTemperature"`
+	CloudCover           OptionalFloat64 `json:"cloudCover"`
+	DewPoint             OptionalFloat64 `json:"dewPoint"`
+	Humidity             OptionalFloat64 `json:"humidity"`
+	Ozone                OptionalFloat64 `json:"ozone"`
+	PrecipAccumulation   OptionalFloat64 `json:"precipAccumulation"`
+	PrecipIntensity      OptionalFloat64 `json:"precipIntensity"`
+	PrecipIntensityError OptionalFloat64 `json:"precipIntensityError"`
+	PrecipProbability    OptionalFloat64 `json:"precipProbability"`
+	Pressure             OptionalFloat64 `json:"pressure"`
+	Temperature          OptionalFloat64 `json:"temperature"`
+	UVIndex              OptionalFloat64 `json:"uvIndex"`
+	Visibility           OptionalFloat64 `json:"visibility"`
+	WindBearing          OptionalFloat64 `json:"windBearing"`
+	WindGust             OptionalFloat64 `json:"windGust"`
+	WindSpeed            OptionalFloat64 `json:"windSpeed"`
+}
+
+// OptionalMinutelyData contains the numeric fields of a MinutelyData,
+// recording whether each was present in the response.
+type OptionalMinutelyData struct {
+	PrecipIntensity      OptionalFloat64 `json:"precipIntensity"`
+	PrecipIntensityError OptionalFloat64 `json:"precipIntensityError"`
+	PrecipProbability    OptionalFloat64 `json:"precipProbability"`
+}
+
+// A fieldSet is a set of the numeric fields of a data point. Bit i is set if
+// field i of the data point's Optional type is present.
+type fieldSet uint32
+
+// optionalForecast mirrors the structure of a Forecast's JSON representation.
+type optionalForecast struct {
+	Currently *OptionalCurrently `json:"currently"`
+	Daily     *struct {
+		Data []*OptionalDailyData `json:"data"`
+	} `json:"daily"`
+	Hourly *struct {
+		Data []*OptionalHourlyData `json:"data"`
+	} `json:"hourly"`
+	Minutely *struct {
+		Data []*OptionalMinutelyData `json:"data"`
+	} `json:"minutely"`
+}
+
+// Indexes of the fields of each data point type that correspond to the fields
+// of its Optional type.
+var (
+	currentlyFieldIndexes    = optionalFieldIndexes(reflect.TypeOf(OptionalCurrently{}), reflect.TypeOf(Currently{}))
+	dailyDataFieldIndexes    = optionalFieldIndexes(reflect.TypeOf(OptionalDailyData{}), reflect.TypeOf(DailyData{}))
+	hourlyDataFieldIndexes   = optionalFieldIndexes(reflect.TypeOf(OptionalHourlyData{}), reflect.TypeOf(HourlyData{}))
+	minutelyDataFieldIndexes = optionalFieldIndexes(reflect.TypeOf(OptionalMinutelyData{}), reflect.TypeOf(MinutelyData{}))
+)
+
+// WithOptionalFields enables recording which numeric fields are present in
+// responses, so that values that are absent can be distinguished from zero.
+// The recorded fields are returned by the Optional methods of Currently,
+// DailyData, HourlyData, and MinutelyData.
+func WithOptionalFields() ClientOption {
+	return func(c *Client) {
+		c.optionalFields = true
+	}
+}
+
+// Get returns o's value and whether it is valid.
+func (o OptionalFloat64) Get() (float64, bool) {
+	return o.Value, o.Valid
+}
+
+// MarshalJSON implements json.Marshaler.
+func (o OptionalFloat64) MarshalJSON() ([]byte, error) {
+	if !o.Valid {
+		return []byte("null"), nil
+	}
+	return json.Marshal(o.Value)
+}
+
+// UnmarshalJSON implements json.Unmarshaler. null is treated as absent.
+func (o *OptionalFloat64) UnmarshalJSON(data []byte) error {
+	if string(data) == "null" {
+		*o = OptionalFloat64{}
+		return nil
+	}
+	if err := json.Unmarshal(data, &o.Value); err != nil {
+		return err
+	}
+	o.Valid = true
+	return nil
+}
+
+// Optional returns the numeric fields of c recording whether each was present.
+// Fields are only recorded as present if c was decoded by a Client with
+// WithOptionalFields.
+func (c *Currently) Optional() OptionalCurrently {
+	var o OptionalCurrently
+	if c != nil {
+		setOptionalFields(&o, c, currentlyFieldIndexes, c.present)
+	}
+	return o
+}
+
+// Optional returns the numeric fields of d recording whether each was present.
+// Fields are only recorded as present if d was decoded by a Client with
+// WithOptionalFields.
+func (d *DailyData) Optional() OptionalDailyData {
+	var o OptionalDailyData
+	if d != nil {
+		setOptionalFields(&o, d, dailyDataFieldIndexes, d.present)
+	}
+	return o
+}
+
+// Optional returns the numeric fields of d recording whether each was present.
+// Fields are only recorded as present if d was decoded by a Client with
+// WithOptionalFields, or interpolated from data points in which they were
+// present.
+func (d *HourlyData) Optional() OptionalHourlyData {
+	var o OptionalHourlyData
+	if d != nil {
+		setOptionalFields(&o, d, hourlyDataFieldIndexes, d.present)
+	}
+	return o
+}
+
+// Optional returns the numeric fields of d recording whether each was present.
+// Fields are only recorded as present if d was decoded by a Client with
+// WithOptionalFields.
+func (d *MinutelyData) Optional() OptionalMinutelyData {
+	var o OptionalMinutelyData
+	if d != nil {
+		setOptionalFields(&o, d, minutelyDataFieldIndexes, d.present)
+	}
+	return o
+}
+
+// decodeOptionalFields records which numeric fields of f's data points are
+// present in data, which must be the JSON data from which f was decoded.
+func (f *Forecast) decodeOptionalFields(data []byte) error {
+	var of optionalForecast
+	if err := json.Unmarshal(data, &of); err != nil {
+		return err
+	}
+	if f.Currently != nil && of.Currently != nil {
+		f.Currently.present = presentFields(of.Currently)
+	}
+	if f.Daily != nil && of.Daily != nil && len(of.Daily.Data) == len(f.Daily.Data) {
+		for i, d := range f.Daily.Data {
+			if d != nil && of.Daily.Data[i] != nil {
+				d.present = presentFields(of.Daily.Data[i])
+			}
+		}
+	}
+	if f.Hourly != nil && of.Hourly != nil && len(of.Hourly.Data) == len(f.Hourly.Data) {
+		for i, d := range f.Hourly.Data {
+			if d != nil && of.Hourly.Data[i] != nil {
+				d.present = presentFields(of.Hourly.Data[i])
+			}
+		}
+	}
+	if f.Minutely != nil && of.Minutely != nil && len(of.Minutely.Data) == len(f.Minutely.Data) {
+		for i, d := range f.Minutely.Data {
+			if d != nil && of.Minutely.Data[i] != nil {
+				d.present = presentFields(of.Minutely.Data[i])
+			}
+		}
+	}
+	return nil
+}
+
+// optionalFieldIndexes returns the index of the field of dataType with the same
+// name as each field of optionalType.
+func optionalFieldIndexes(optionalType, dataType reflect.Type) []int {
+	indexes := make([]int, optionalType.NumField())
+	for i := range indexes {
+		field, ok := dataType.FieldByName(optionalType.Field(i).Name)
+		if !ok {
+			panic(dataType.Name() + " has no field " + optionalType.Field(i).Name)
+		}
+		indexes[i] = field.Index[0]
+	}
+	return indexes
+}
+
+// presentFields returns the set of valid fields of optional, which must be a
+// pointer to an Optional type.
+func presentFields(optional interface{}) fieldSet {
+	v := reflect.ValueOf(optional).Elem()
+	var present fieldSet
+	for i := 0; i < v.NumField(); i++ {
+		if v.Field(i).Interface().(OptionalFloat64).Valid {
+			present |= 1 << i
+		}
+	}
+	return present
+}
+
+// setOptionalFields sets the fields of optional, which must be a pointer to an
+// Optional type, that are in present to the fields of data, which must be a
+// pointer to the corresponding data point, at indexes.
+func setOptionalFields(optional, data interface{}, indexes []int, present fieldSet) {
+	optionalValue := reflect.ValueOf(optional).Elem()
+	dataValue := reflect.ValueOf(data).Elem()
+	for i, index := range indexes {
+		if present&(1<<i) != 0 {
+			optionalValue.Field(i).Set(reflect.ValueOf(OptionalFloat64{
+				Value: dataValue.Field(index).Float(),
+				Valid: true,
+			}))
+		}
+	}
+}
@@ -0,0 +1,206 @@
+package darksky
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClientOptionalFields(t *testing.T) {
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{` +
+			`"currently":{"temperature":12.5,"nearestStormDistance":0,"windGust":null},` +
+			`"daily":{"data":[{"uvIndex":0},{"uvIndex":3}]},` +
+			`"hourly":{"data":[{"precipProbability":0.5},{}]},` +
+			`"minutely":{"data":[{"precipIntensity":0}]}` +
+			`}`))
+	}))
+	defer s.Close()
+
+	for _, optionalFields := range []bool{false, true} {
+		options := []ClientOption{
+			WithBaseURL(s.URL),
+			WithHTTPClient(s.Client()),
+		}
+		if optionalFields {
+			options = append(options, WithOptionalFields())
+		}
+		c, err := NewClient(options...)
+		require.NoError(t, err)
+		forecast, err := c.Forecast(context.Background(), 42.3601, -71.0589, nil, nil)
+		require.NoError(t, err)
+		assert.Equal(t, 12.5, forecast.Currently.Temperature)
+		if !optionalFields {
+			assertOptionalFloat64(t, 0, false, forecast.Currently.Optional().Temperature)
+			assertOptionalFloat64(t, 0, false, forecast.Daily.Data[1].Optional().UVIndex)
+			assertOptionalFloat64(t, 0, false, forecast.Hourly.Data[0].Optional().PrecipProbability)
+			assertOptionalFloat64(t, 0, false, forecast.Minutely.Data[0].Optional().PrecipIntensity)
+			continue
+		}
+
+		currently := forecast.Currently.Optional()
+		assertOptionalFloat64(t, 12.5, true, currently.Temperature)
+		assertOptionalFloat64(t, 0, true, currently.NearestStormDistance)
+		assertOptionalFloat64(t, 0, false, currently.Visibility)
+		assertOptionalFloat64(t, 0, false, currently.WindGust)
+
+		assertOptionalFloat64(t, 0, true, forecast.Daily.Data[0].Optional().UVIndex)
+		assertOptionalFloat64(t, 3, true, forecast.Daily.Data[1].Optional().UVIndex)
+		assertOptionalFloat64(t, 0, false, forecast.Daily.Data[1].Optional().WindSpeed)
+		assertOptionalFloat64(t, 0.5, true, forecast.Hourly.Data[0].Optional().PrecipProbability)
+		assertOptionalFloat64(t, 0, false, forecast.Hourly.Data[1].Optional().PrecipProbability)
+		assertOptionalFloat64(t, 0, true, forecast.Minutely.Data[0].Optional().PrecipIntensity)
+		assertOptionalFloat64(t, 0, false, forecast.Minutely.Data[0].Optional().PrecipIntensityError)
+	}
+}
+
+// TestOptionalFieldsComplete tests that every numeric field of each data point
+// type has a corresponding optional field.
+func TestOptionalFieldsComplete(t *testing.T) {
+	for _, tc := range []struct {
+		dataType     reflect.Type
+		optionalType reflect.Type
+	}{
+		{
+			dataType:     reflect.TypeOf(Currently{}),
+			optionalType: reflect.TypeOf(OptionalCurrently{}),
+		},
+		{
+			dataType:     reflect.TypeOf(DailyData{}),
+			optionalType: reflect.TypeOf(OptionalDailyData{}),
+		},
+		{
+			dataType:     reflect.TypeOf(HourlyData{}),
+			optionalType: reflect.TypeOf(OptionalHourlyData{}),
+		},
+		{
+			dataType:     reflect.TypeOf(MinutelyData{}),
+			optionalType: reflect.TypeOf(OptionalMinutelyData{}),
+		},
+	} {
+		t.Run(tc.dataType.Name(), func(t *testing.T) {
+			var expected []string
+			for i := 0; i < tc.dataType.NumField(); i++ {
+				field := tc.dataType.Field(i)
+				if field.Type.Kind() == reflect.Float64 {
+					expected = append(expected, field.Name+" "+field.Tag.Get("json"))
+				}
+			}
+			var actual []string
+			for i := 0; i < tc.optionalType.NumField(); i++ {
+				field := tc.optionalType.Field(i)
+				actual = append(actual, field.Name+" "+field.Tag.Get("json"))
+			}
+			assert.Equal(t, expected, actual)
+		})
+	}
+}
+
+// TestClientOptionalFieldsConvertUnits tests that ConvertUnits converts the
+// optional fields in the same way as the fields they record.
+func TestClientOptionalFieldsConvertUnits(t *testing.T) {
+	// allFields returns a JSON object with every optional field of
+	// optionalType set to 212.
+	allFields := func(optionalType reflect.Type) map[string]float64 {
+		fields := make(map[string]float64)
+		for i := 0; i < optionalType.NumField(); i++ {
+			fields[optionalType.Field(i).Tag.Get("json")] = 212
+		}
+		return fields
+	}
+	body, err := json.Marshal(map[string]interface{}{
+		"currently": allFields(reflect.TypeOf(OptionalCurrently{})),
+		"daily": map[string]interface{}{
+			"data": []interface{}{allFields(reflect.TypeOf(OptionalDailyData{}))},
+		},
+		"hourly": map[string]interface{}{
+			"data": []interface{}{allFields(reflect.TypeOf(OptionalHourlyData{})), map[string]float64{}},
+		},
+		"minutely": map[string]interface{}{
+			"data": []interface{}{allFields(reflect.TypeOf(OptionalMinutelyData{}))},
+		},
+		"flags": map[string]string{
+			"units": "us",
+		},
+	})
+	require.NoError(t, err)
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write(body)
+	}))
+	defer s.Close()
+
+	c, err := NewClient(
+		WithBaseURL(s.URL),
+		WithHTTPClient(s.Client()),
+		WithOptionalFields(),
+	)
+	require.NoError(t, err)
+	forecast, err := c.Forecast(context.Background(), 42.3601, -71.0589, nil, nil)
+	require.NoError(t, err)
+	require.NoError(t, forecast.ConvertUnits(UnitsSI))
+
+	assert.Equal(t, 100.0, forecast.Currently.Temperature)
+	assertOptionalFloat64(t, 100, true, forecast.Currently.Optional().Temperature)
+	assertOptionalFloat64(t, 0, false, forecast.Hourly.Data[1].Optional().Temperature)
+
+	// assertConverted asserts that every optional field of optional is equal
+	// to the corresponding field of data.
+	assertConverted := func(data, optional interface{}) {
+		dataValue := reflect.ValueOf(data).Elem()
+		optionalValue := reflect.ValueOf(optional)
+		for i := 0; i < optionalValue.NumField(); i++ {
+			name := optionalValue.Type().Field(i).Name
+			o := optionalValue.Field(i).Interface().(OptionalFloat64)
+			assert.True(t, o.Valid, name)
+			assert.Equal(t, dataValue.FieldByName(name).Float(), o.Value, name)
+		}
+	}
+	assertConverted(forecast.Currently, forecast.Currently.Optional())
+	assertConverted(forecast.Daily.Data[0], forecast.Daily.Data[0].Optional())
+	assertConverted(forecast.Hourly.Data[0], forecast.Hourly.Data[0].Optional())
+	assertConverted(forecast.Minutely.Data[0], forecast.Minutely.Data[0].Optional())
+}
+
+func TestOptionalNil(t *testing.T) {
+	assert.Equal(t, OptionalCurrently{}, (*Currently)(nil).Optional())
+	assert.Equal(t, OptionalDailyData{}, (*DailyData)(nil).Optional())
+	assert.Equal(t, OptionalHourlyData{}, (*HourlyData)(nil).Optional())
+	assert.Equal(t, OptionalMinutelyData{}, (*MinutelyData)(nil).Optional())
+}
+
+func TestClientOptionalFieldsInterpolate(t *testing.T) {
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"hourly":{"data":[` +
+			`{"time":1556668800,"temperature":10,"windGust":5},` +
+			`{"time":1556672400,"temperature":20}` +
+			`]}}`))
+	}))
+	defer s.Close()
+
+	c, err := NewClient(
+		WithBaseURL(s.URL),
+		WithHTTPClient(s.Client()),
+		WithOptionalFields(),
+	)
+	require.NoError(t, err)
+	forecast, err := c.Forecast(context.Background(), 42.3601, -71.0589, nil, nil)
+	require.NoError(t, err)
+	d, err := forecast.Hourly.Interpolate(time.Unix(1556670600, 0))
+	require.NoError(t, err)
+	assertOptionalFloat64(t, 15, true, d.Optional().Temperature)
+	assertOptionalFloat64(t, 0, false, d.Optional().WindGust)
+}
+
+func assertOptionalFloat64(t *testing.T, expectedValue float64, expectedOK bool, o OptionalFloat64) {
+	t.Helper()
+	value, ok := o.Get()
+	assert.Equal(t, expectedValue, value)
+	assert.Equal(t, expectedOK, ok)
+}
@@ -0,0 +1,179 @@
+package darksky
+
+import "time"
+
+// The queries on Hourly and Minutely assume that Data is sorted by time, as
+// returned by the API. Nil data points are ignored, and data points with nil
+// Times are ignored by queries that depend on time. Queries that return
+// multiple data points return them in the order of Data. All queries may be
+// called on a nil receiver.
+
+// At returns the data point covering t, or nil if there is none. A data point
+// covers the hour starting at its time.
+func (h *Hourly) At(t time.Time) *HourlyData {
+	if h == nil {
+		return nil
+	}
+	for _, d := range h.Data {
+		if d != nil && covers(d.Time, time.Hour, t) {
+			return d
+		}
+	}
+	return nil
+}
+
+// Between returns the data points with times from from, inclusive, to to,
+// exclusive.
+func (h *Hourly) Between(from, to time.Time) []*HourlyData {
+	return h.Where(func(d *HourlyData) bool {
+		return between(d.Time, from, to)
+	})
+}
+
+// FirstWhere returns the first data point for which f returns true, or nil if
+// there is none.
+func (h *Hourly) FirstWhere(f func(*HourlyData) bool) *HourlyData {
+	if h == nil {
+		return nil
+	}
+	for _, d := range h.Data {
+		if d != nil && f(d) {
+			return d
+		}
+	}
+	return nil
+}
+
+// MaxBy returns the first data point with the largest value of f, or nil if
+// there are no data points.
+func (h *Hourly) MaxBy(f func(*HourlyData) float64) *HourlyData {
+	return h.extremeBy(f, func(x, y float64) bool { return x > y })
+}
+
+// MinBy returns the first data point with the smallest value of f, or nil if
+// there are no data points.
+func (h *Hourly) MinBy(f func(*HourlyData) float64) *HourlyData {
+	return h.extremeBy(f, func(x, y float64) bool { return x < y })
+}
+
+// Where returns the data points for which f returns true.
+func (h *Hourly) Where(f func(*HourlyData) bool) []*HourlyData {
+	if h == nil {
+		return nil
+	}
+	var result []*HourlyData
+	for _, d := range h.Data {
+		if d != nil && f(d) {
+			result = append(result, d)
+		}
+	}
+	return result
+}
+
+func (h *Hourly) extremeBy(f func(*HourlyData) float64, better func(float64, float64) bool) *HourlyData {
+	if h == nil {
+		return nil
+	}
+	var result *HourlyData
+	var resultValue float64
+	for _, d := range h.Data {
+		if d == nil {
+			continue
+		}
+		if value := f(d); result == nil || better(value, resultValue) {
+			result, resultValue = d, value
+		}
+	}
+	return result
+}
+
+// At returns the data point covering t, or nil if there is none. A data point
+// covers the minute starting at its time.
+func (m *Minutely) At(t time.Time) *MinutelyData {
+	if m == nil {
+		return nil
+	}
+	for _, d := range m.Data {
+		if d != nil && covers(d.Time, time.Minute, t) {
+			return d
+		}
+	}
+	return nil
+}
+
+// Between returns the data points with times from from, inclusive, to to,
+// exclusive.
+func (m *Minutely) Between(from, to time.Time) []*MinutelyData {
+	return m.Where(func(d *MinutelyData) bool {
+		return between(d.Time, from, to)
+	})
+}
+
+// FirstWhere returns the first data point for which f returns true, or nil if
+// there is none.
+func (m *Minutely) FirstWhere(f func(*MinutelyData) bool) *MinutelyData {
+	if m == nil {
+		return nil
+	}
+	for _, d := range m.Data {
+		if d != nil && f(d) {
+			return d
+		}
+	}
+	return nil
+}
+
+// MaxBy returns the first data point with the largest value of f, or nil if
+// there are no data points.
+func (m *Minutely) MaxBy(f func(*MinutelyData) float64) *MinutelyData {
+	return m.extremeBy(f, func(x, y float64) bool { return x > y })
+}
+
+// MinBy returns the first data point with the smallest value of f, or nil if
+// there are no data points.
+func (m *Minutely) MinBy(f func(*MinutelyData) float64) *MinutelyData {
+	return m.extremeBy(f, func(x, y float64) bool { return x < y })
+}
+
+// Where returns the data points for which f returns true.
+func (m *Minutely) Where(f func(*MinutelyData) bool) []*MinutelyData {
+	if m == nil {
+		return nil
+	}
+	var result []*MinutelyData
+	for _, d := range m.Data {
+		if d != nil && f(d) {
+			result = append(result, d)
+		}
+	}
+	return result
+}
+
+func (m *Minutely) extremeBy(f func(*MinutelyData) float64, better func(float64, float64) bool) *MinutelyData {
+	if m == nil {
+		return nil
+	}
+	var result *MinutelyData
+	var resultValue float64
+	for _, d := range m.Data {
+		if d == nil {
+			continue
+		}
+		if value := f(d); result == nil || better(value, resultValue) {
+			result, resultValue = d, value
+		}
+	}
+	return result
+}
+
+// between returns whether start is not nil and is from from, inclusive, to to,
+// exclusive.
+func between(start *Time, from, to time.Time) bool {
+	return start != nil && !start.Before(from) && start.Before(to)
+}
+
+// covers returns whether the interval of length d beginning at start contains
+// t.
+func covers(start *Time, d time.Duration, t time.Time) bool {
+	return between(start, t.Add(-d+1), t.Add(1))
+}
@@ -0,0 +1,85 @@
+package darksky_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/twpayne/go-darksky"
+)
+
+func TestHourlyQueries(t *testing.T) {
+	t0 := time.Unix(1556668800, 0)
+	hour := func(i int) *darksky.Time {
+		return &darksky.Time{Time: t0.Add(time.Duration(i) * time.Hour)}
+	}
+	h0 := &darksky.HourlyData{Time: hour(0), Temperature: 10}
+	h1 := &darksky.HourlyData{Time: hour(1), Temperature: 15, PrecipProbability: 0.5}
+	hNoTime := &darksky.HourlyData{Temperature: 20}
+	h2 := &darksky.HourlyData{Time: hour(2), Temperature: 5, PrecipProbability: 0.8}
+	h3 := &darksky.HourlyData{Time: hour(3), Temperature: 15}
+	hourly := &darksky.Hourly{
+		Data: []*darksky.HourlyData{h0, nil, h1, hNoTime, h2, h3},
+	}
+
+	assert.Nil(t, hourly.At(t0.Add(-time.Nanosecond)))
+	assert.Equal(t, h0, hourly.At(t0))
+	assert.Equal(t, h0, hourly.At(t0.Add(59*time.Minute)))
+	assert.Equal(t, h1, hourly.At(t0.Add(time.Hour)))
+	assert.Equal(t, h3, hourly.At(t0.Add(3*time.Hour+30*time.Minute)))
+	assert.Nil(t, hourly.At(t0.Add(4*time.Hour)))
+
+	assert.Equal(t, []*darksky.HourlyData{h1, h2}, hourly.Between(hour(1).Time, hour(3).Time))
+	assert.Nil(t, hourly.Between(hour(3).Time, hour(1).Time))
+
+	rainy := func(d *darksky.HourlyData) bool { return d.PrecipProbability >= 0.5 }
+	assert.Equal(t, []*darksky.HourlyData{h1, h2}, hourly.Where(rainy))
+	assert.Equal(t, h1, hourly.FirstWhere(rainy))
+	assert.Nil(t, hourly.FirstWhere(func(d *darksky.HourlyData) bool { return d.Temperature > 100 }))
+
+	temperature := func(d *darksky.HourlyData) float64 { return d.Temperature }
+	assert.Equal(t, hNoTime, hourly.MaxBy(temperature))
+	assert.Equal(t, h2, hourly.MinBy(temperature))
+	assert.Equal(t, h1, (&darksky.Hourly{Data: []*darksky.HourlyData{h1, h3}}).MaxBy(temperature))
+
+	var nilHourly *darksky.Hourly
+	assert.Nil(t, nilHourly.At(t0))
+	assert.Nil(t, nilHourly.Between(hour(0).Time, hour(1).Time))
+	assert.Nil(t, nilHourly.FirstWhere(rainy))
+	assert.Nil(t, nilHourly.MaxBy(temperature))
+	assert.Nil(t, nilHourly.MinBy(temperature))
+	assert.Nil(t, nilHourly.Where(rainy))
+}
+
+func TestMinutelyQueries(t *testing.T) {
+	t0 := time.Unix(1556668800, 0)
+	minute := func(i int) *darksky.Time {
+		return &darksky.Time{Time: t0.Add(time.Duration(i) * time.Minute)}
+	}
+	m0 := &darksky.MinutelyData{Time: minute(0)}
+	m1 := &darksky.MinutelyData{Time: minute(1), PrecipIntensity: 0.2}
+	m2 := &darksky.MinutelyData{Time: minute(2), PrecipIntensity: 1.5}
+	m3 := &darksky.MinutelyData{Time: minute(3), PrecipIntensity: 0.4}
+	minutely := &darksky.Minutely{
+		Data: []*darksky.MinutelyData{m0, m1, nil, m2, m3},
+	}
+
+	assert.Equal(t, m1, minutely.At(t0.Add(90*time.Second)))
+	assert.Nil(t, minutely.At(t0.Add(4*time.Minute)))
+	assert.Equal(t, []*darksky.MinutelyData{m0, m1}, minutely.Between(minute(0).Time, minute(2).Time))
+
+	raining := func(d *darksky.MinutelyData) bool { return d.PrecipIntensity > 0 }
+	assert.Equal(t, []*darksky.MinutelyData{m1, m2, m3}, minutely.Where(raining))
+	assert.Equal(t, m1, minutely.FirstWhere(raining))
+
+	precipIntensity := func(d *darksky.MinutelyData) float64 { return d.PrecipIntensity }
+	assert.Equal(t, m2, minutely.MaxBy(precipIntensity))
+	assert.Equal(t, m0, minutely.MinBy(precipIntensity))
+
+	var nilMinutely *darksky.Minutely
+	assert.Nil(t, nilMinutely.At(t0))
+	assert.Nil(t, nilMinutely.FirstWhere(raining))
+	assert.Nil(t, nilMinutely.MaxBy(precipIntensity))
+	assert.Nil(t, nilMinutely.Where(raining))
+}
@@ -0,0 +1,186 @@
+package darksky
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+var (
+	// ErrQuotaExceeded is returned, before a request is sent, when the daily
+	// quota set with WithDailyQuota has been used.
+	ErrQuotaExceeded = errors.New("darksky: daily quota exceeded")
+
+	// ErrInvalidRateLimit is returned by NewClient when the parameters passed
+	// to WithRateLimit are invalid.
+	ErrInvalidRateLimit = errors.New("darksky: invalid rate limit")
+)
+
+// A quota tracks the number of API calls used in the current billing day, which
+// starts at midnight UTC.
+type quota struct {
+	mutex sync.Mutex
+	limit int
+	used  int
+	day   time.Time
+}
+
+// A rateLimiter is a token bucket rate limiter.
+type rateLimiter struct {
+	mutex  sync.Mutex
+	rate   float64
+	burst  float64
+	tokens float64
+	last   time.Time
+}
+
+// WithDailyQuota sets the maximum number of API calls per day. The number of
+// calls used is updated from the X-Forecast-API-Calls response header, so it
+// includes calls made by other clients using the same key. The count resets at
+// midnight UTC.
+func WithDailyQuota(n int) ClientOption {
+	return func(c *Client) {
+		c.quota = &quota{
+			limit: n,
+		}
+	}
+}
+
+// WithRateLimit limits the rate at which requests are sent to
+// requestsPerSecond, with bursts of up to burst requests. NewClient returns an
+// error if requestsPerSecond is not positive or burst is less than one.
+func WithRateLimit(requestsPerSecond float64, burst int) ClientOption {
+	return func(c *Client) {
+		c.rateLimiter = &rateLimiter{
+			rate:   requestsPerSecond,
+			burst:  float64(burst),
+			tokens: float64(burst),
+		}
+	}
+}
+
+// DailyQuotaUsed returns the number of API calls used today, as tracked by the
+// daily quota. It returns zero if no daily quota is set.
+func (c *Client) DailyQuotaUsed() int {
+	if c.quota == nil {
+		return 0
+	}
+	c.quota.mutex.Lock()
+	defer c.quota.mutex.Unlock()
+	c.quota.resetIfNewDay(c.now())
+	return c.quota.used
+}
+
+// beforeAttempt reserves quota and waits for the rate limiter before an
+// attempt.
+func (c *Client) beforeAttempt(ctx context.Context) error {
+	if c.quota != nil {
+		if err := c.quota.reserve(c.now()); err != nil {
+			return err
+		}
+	}
+	if c.rateLimiter != nil {
+		if err := c.rateLimiter.wait(ctx, c.now); err != nil {
+			c.releaseQuota()
+			return err
+		}
+	}
+	return nil
+}
+
+// releaseQuota releases the quota reserved by beforeAttempt, if any.
+func (c *Client) releaseQuota() {
+	if c.quota != nil {
+		c.quota.release()
+	}
+}
+
+// reserve reserves a call, returning ErrQuotaExceeded if none remain.
+func (q *quota) reserve(now time.Time) error {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+	q.resetIfNewDay(now)
+	if q.used >= q.limit {
+		return ErrQuotaExceeded
+	}
+	q.used++
+	return nil
+}
+
+// release releases a reserved call that was not made or that failed.
+func (q *quota) release() {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+	if q.used > 0 {
+		q.used--
+	}
+}
+
+// observe records that the API reported forecastAPICalls calls used today.
+func (q *quota) observe(now time.Time, forecastAPICalls int) {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+	q.resetIfNewDay(now)
+	if forecastAPICalls > q.used {
+		q.used = forecastAPICalls
+	}
+}
+
+func (q *quota) resetIfNewDay(now time.Time) {
+	if day := now.UTC().Truncate(24 * time.Hour); !day.Equal(q.day) {
+		q.day = day
+		q.used = 0
+	}
+}
+
+// validate returns an error if l's parameters are invalid.
+func (l *rateLimiter) validate() error {
+	if !(l.rate > 0) {
+		return fmt.Errorf("%w: %v requests per second", ErrInvalidRateLimit, l.rate)
+	}
+	if l.burst < 1 {
+		return fmt.Errorf("%w: burst %v", ErrInvalidRateLimit, l.burst)
+	}
+	return nil
+}
+
+// wait waits until a token is available or ctx is done. If ctx's deadline
+// would pass before a token becomes available then it returns
+// context.DeadlineExceeded immediately.
+func (l *rateLimiter) wait(ctx context.Context, now func() time.Time) error {
+	l.mutex.Lock()
+	t := now()
+	if !l.last.IsZero() {
+		l.tokens += t.Sub(l.last).Seconds() * l.rate
+		if l.tokens > l.burst {
+			l.tokens = l.burst
+		}
+	}
+	l.last = t
+	if l.tokens >= 1 {
+		l.tokens--
+		l.mutex.Unlock()
+		return nil
+	}
+	delay := time.Duration((1 - l.tokens) / l.rate * float64(time.Second))
+	if deadline, ok := contextDeadline(ctx); ok && t.Add(delay).After(deadline) {
+		l.mutex.Unlock()
+		return context.DeadlineExceeded
+	}
+	l.tokens--
+	l.mutex.Unlock()
+
+	timer := time.NewTimer(delay)
+	select {
+	case <-ctx.Done():
+		timer.Stop()
+		l.mutex.Lock()
+		l.tokens++
+		l.mutex.Unlock()
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
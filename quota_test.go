@@ -0,0 +1,157 @@
+package darksky
+
+import (
+	"context"
+	"errors"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClientDailyQuota(t *testing.T) {
+	var forecastAPICalls int32 = 5
+	var requests int32
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		w.Header().Set("X-Forecast-API-Calls", strconv.Itoa(int(atomic.AddInt32(&forecastAPICalls, 1))))
+		_, _ = w.Write([]byte(`{}`))
+	}))
+	defer s.Close()
+
+	now := time.Date(2019, 5, 1, 23, 0, 0, 0, time.UTC)
+	c, err := NewClient(
+		WithBaseURL(s.URL),
+		WithHTTPClient(s.Client()),
+		WithKey("key"),
+		WithDailyQuota(7),
+	)
+	require.NoError(t, err)
+	c.now = func() time.Time { return now }
+
+	ctx := context.Background()
+	_, err = c.Forecast(ctx, 42.3601, -71.0589, nil, nil)
+	require.NoError(t, err)
+	assert.Equal(t, 6, c.DailyQuotaUsed(), "used calls should be seeded from header")
+	_, err = c.Forecast(ctx, 42.3601, -71.0589, nil, nil)
+	require.NoError(t, err)
+	assert.Equal(t, 7, c.DailyQuotaUsed())
+	_, err = c.Forecast(ctx, 42.3601, -71.0589, nil, nil)
+	assert.Equal(t, ErrQuotaExceeded, err)
+	assert.Equal(t, int32(2), atomic.LoadInt32(&requests), "no request should be sent once quota is exceeded")
+
+	now = time.Date(2019, 5, 2, 0, 0, 0, 0, time.UTC)
+	atomic.StoreInt32(&forecastAPICalls, 0)
+	assert.Equal(t, 0, c.DailyQuotaUsed())
+	_, err = c.Forecast(ctx, 42.3601, -71.0589, nil, nil)
+	require.NoError(t, err)
+	assert.Equal(t, 1, c.DailyQuotaUsed())
+}
+
+func TestClientDailyQuotaNotRetried(t *testing.T) {
+	var requests int32
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-Forecast-API-Calls", strconv.Itoa(int(atomic.AddInt32(&requests, 1))))
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer s.Close()
+
+	c, err := NewClient(
+		WithBaseURL(s.URL),
+		WithHTTPClient(s.Client()),
+		WithKey("key"),
+		WithDailyQuota(2),
+		WithRetryPolicy(&RetryPolicy{
+			MaxAttempts:    5,
+			InitialBackoff: time.Millisecond,
+		}),
+	)
+	require.NoError(t, err)
+	_, err = c.Forecast(context.Background(), 42.3601, -71.0589, nil, nil)
+	var e *Error
+	require.True(t, errors.As(err, &e), "the last attempt's error should be returned")
+	assert.Equal(t, http.StatusServiceUnavailable, e.Response.StatusCode)
+	assert.Equal(t, int32(2), atomic.LoadInt32(&requests))
+	assert.Equal(t, 2, c.DailyQuotaUsed())
+}
+
+func TestClientDailyQuotaFailedAttempts(t *testing.T) {
+	var requests int32
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&requests, 1) <= 3 {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		_, _ = w.Write([]byte(`{}`))
+	}))
+	defer s.Close()
+
+	c, err := NewClient(
+		WithBaseURL(s.URL),
+		WithHTTPClient(s.Client()),
+		WithKey("key"),
+		WithDailyQuota(3),
+	)
+	require.NoError(t, err)
+	ctx := context.Background()
+	for i := 0; i < 3; i++ {
+		_, err = c.Forecast(ctx, 42.3601, -71.0589, nil, nil)
+		assert.True(t, errors.Is(err, ErrServerError))
+		assert.Equal(t, 0, c.DailyQuotaUsed(), "failed attempts should not use quota")
+	}
+	_, err = c.Forecast(ctx, 42.3601, -71.0589, nil, nil)
+	require.NoError(t, err)
+	assert.Equal(t, 1, c.DailyQuotaUsed())
+
+	s.Close()
+	_, err = c.Forecast(ctx, 42.3601, -71.0589, nil, nil)
+	assert.Error(t, err)
+	assert.Equal(t, 1, c.DailyQuotaUsed(), "transport errors should not use quota")
+}
+
+func TestWithRateLimitInvalid(t *testing.T) {
+	for _, tc := range []struct {
+		requestsPerSecond float64
+		burst             int
+		expectErr         bool
+	}{
+		{requestsPerSecond: 1, burst: 1},
+		{requestsPerSecond: 0.5, burst: 10},
+		{requestsPerSecond: 0, burst: 1, expectErr: true},
+		{requestsPerSecond: -1, burst: 1, expectErr: true},
+		{requestsPerSecond: math.NaN(), burst: 1, expectErr: true},
+		{requestsPerSecond: 1, burst: 0, expectErr: true},
+	} {
+		_, err := NewClient(WithRateLimit(tc.requestsPerSecond, tc.burst))
+		if tc.expectErr {
+			assert.True(t, errors.Is(err, ErrInvalidRateLimit), "%v %d", tc.requestsPerSecond, tc.burst)
+		} else {
+			assert.NoError(t, err, "%v %d", tc.requestsPerSecond, tc.burst)
+		}
+	}
+}
+
+func TestRateLimiter(t *testing.T) {
+	l := &rateLimiter{
+		rate:   100,
+		burst:  2,
+		tokens: 2,
+	}
+	ctx := context.Background()
+	start := time.Now()
+	for i := 0; i < 4; i++ {
+		require.NoError(t, l.wait(ctx, time.Now))
+	}
+	assert.True(t, time.Since(start) >= 15*time.Millisecond)
+
+	ctx, cancel := context.WithTimeout(ctx, time.Millisecond)
+	defer cancel()
+	l.rate = 0.001
+	assert.Equal(t, context.DeadlineExceeded, l.wait(ctx, time.Now))
+}
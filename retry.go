@@ -0,0 +1,156 @@
+package darksky
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// ErrInvalidRetryPolicy is returned by NewClient when the policy passed to
+// WithRetryPolicy is invalid.
+var ErrInvalidRetryPolicy = errors.New("darksky: invalid retry policy")
+
+// A RetryPolicy determines how requests that fail with transient errors are
+// retried. Transient errors are transport errors (e.g. connection resets),
+// 429 Too Many Requests, and 5xx responses. Backoff is exponential with
+// jitter, a Retry-After header in the response takes precedence over the
+// computed backoff, and no retry is attempted if it would not start before
+// the request's context's deadline.
+type RetryPolicy struct {
+	MaxAttempts    int           // Maximum number of attempts, including the first.
+	InitialBackoff time.Duration // Backoff before the first retry.
+	MaxBackoff     time.Duration // Maximum backoff, zero means no maximum.
+	Multiplier     float64       // Factor by which the backoff increases after each retry.
+	Jitter         float64       // Fraction of the backoff that is randomized, in [0, 1].
+}
+
+// DefaultRetryPolicy returns a new retry policy suitable for most uses.
+func DefaultRetryPolicy() *RetryPolicy {
+	return &RetryPolicy{
+		MaxAttempts:    4,
+		InitialBackoff: 500 * time.Millisecond,
+		MaxBackoff:     30 * time.Second,
+		Multiplier:     2,
+		Jitter:         0.5,
+	}
+}
+
+// WithRetryPolicy sets the retry policy. By default, requests are not retried.
+func WithRetryPolicy(rp *RetryPolicy) ClientOption {
+	return func(c *Client) {
+		c.retryPolicy = rp
+	}
+}
+
+// backoff returns the backoff after attempt.
+func (rp *RetryPolicy) backoff(attempt int) time.Duration {
+	multiplier := rp.Multiplier
+	if multiplier < 1 {
+		multiplier = 1
+	}
+	backoff := float64(rp.InitialBackoff) * math.Pow(multiplier, float64(attempt-1))
+	if rp.MaxBackoff > 0 && backoff > float64(rp.MaxBackoff) {
+		backoff = float64(rp.MaxBackoff)
+	}
+	if rp.Jitter > 0 {
+		backoff *= 1 - rp.Jitter*rand.Float64() //nolint:gosec
+	}
+	return time.Duration(backoff)
+}
+
+// validate returns an error if rp's parameters are invalid.
+func (rp *RetryPolicy) validate() error {
+	switch {
+	case rp.InitialBackoff < 0:
+		return fmt.Errorf("%w: initial backoff %v", ErrInvalidRetryPolicy, rp.InitialBackoff)
+	case rp.MaxBackoff < 0:
+		return fmt.Errorf("%w: max backoff %v", ErrInvalidRetryPolicy, rp.MaxBackoff)
+	case !(rp.Multiplier >= 0):
+		return fmt.Errorf("%w: multiplier %v", ErrInvalidRetryPolicy, rp.Multiplier)
+	case !(0 <= rp.Jitter && rp.Jitter <= 1):
+		return fmt.Errorf("%w: jitter %v", ErrInvalidRetryPolicy, rp.Jitter)
+	}
+	return nil
+}
+
+// retryable returns whether err, returned by an attempt, is transient.
+func (rp *RetryPolicy) retryable(ctx context.Context, err error) bool {
+	if ctx.Err() != nil || errors.Is(err, ErrQuotaExceeded) || errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+	var e *Error
+	if !errors.As(err, &e) {
+		return true
+	}
+	return e.IsTemporary()
+}
+
+// delay returns the delay before retrying after attempt failed with err.
+func (rp *RetryPolicy) delay(attempt int, err error, now time.Time) time.Duration {
+	var e *Error
+	if errors.As(err, &e) {
+		if retryAfter, ok := parseRetryAfter(e.Response.Header.Get("Retry-After"), now); ok {
+			return retryAfter
+		}
+	}
+	return rp.backoff(attempt)
+}
+
+// do sends req, retrying according to c's retry policy. On success, the caller
+// is responsible for closing the response body. If a retry is prevented by the
+// daily quota then the previous attempt's error is returned.
+func (c *Client) do(req *http.Request) (*http.Response, error) {
+	ctx := req.Context()
+	var lastErr error
+	for attempt := 1; ; attempt++ {
+		resp, err := c.doOnce(req, attempt)
+		if err == nil {
+			return resp, nil
+		}
+		if lastErr != nil && errors.Is(err, ErrQuotaExceeded) {
+			return nil, lastErr
+		}
+		lastErr = err
+		if c.retryPolicy == nil || attempt >= c.retryPolicy.MaxAttempts || !c.retryPolicy.retryable(ctx, err) {
+			return nil, err
+		}
+		now := c.now()
+		delay := c.retryPolicy.delay(attempt, err, now)
+		if deadline, ok := contextDeadline(ctx); ok && now.Add(delay).After(deadline) {
+			return nil, err
+		}
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
+
+// parseRetryAfter parses the value of a Retry-After header, which is either a
+// number of seconds or an HTTP date.
+func parseRetryAfter(s string, now time.Time) (time.Duration, bool) {
+	if s == "" {
+		return 0, false
+	}
+	if seconds, err := strconv.Atoi(s); err == nil {
+		if seconds < 0 {
+			return 0, false
+		}
+		return time.Duration(seconds) * time.Second, true
+	}
+	if t, err := http.ParseTime(s); err == nil {
+		if d := t.Sub(now); d > 0 {
+			return d, true
+		}
+		return 0, true
+	}
+	return 0, false
+}
@@ -0,0 +1,213 @@
+package darksky_test
+
+import (
+	"context"
+	"errors"
+	"math"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/twpayne/go-darksky"
+	"github.com/twpayne/go-darksky/dstest"
+)
+
+func TestClientRetryPolicy(t *testing.T) {
+	retryPolicy := &darksky.RetryPolicy{
+		MaxAttempts:    3,
+		InitialBackoff: time.Millisecond,
+		Multiplier:     2,
+	}
+	for _, tc := range []struct {
+		name                string
+		retryPolicy         *darksky.RetryPolicy
+		responses           []dstest.Response
+		timeout             time.Duration
+		expectedStatusCode  int
+		expectedStatusCodes []int
+		expectedAttempts    []int
+	}{
+		{
+			name: "no_retry_policy",
+			responses: []dstest.Response{
+				{StatusCode: http.StatusServiceUnavailable},
+			},
+			expectedStatusCode:  http.StatusServiceUnavailable,
+			expectedStatusCodes: []int{http.StatusServiceUnavailable},
+			expectedAttempts:    []int{1},
+		},
+		{
+			name:        "success_after_retries",
+			retryPolicy: retryPolicy,
+			responses: []dstest.Response{
+				{StatusCode: http.StatusServiceUnavailable},
+				{StatusCode: http.StatusTooManyRequests, Header: http.Header{"Retry-After": []string{"0"}}},
+			},
+			expectedStatusCodes: []int{http.StatusServiceUnavailable, http.StatusTooManyRequests, http.StatusOK},
+			expectedAttempts:    []int{1, 2, 3},
+		},
+		{
+			name:        "connection_reset",
+			retryPolicy: retryPolicy,
+			responses: []dstest.Response{
+				{CloseConnection: true},
+			},
+			expectedStatusCodes: []int{http.StatusOK},
+			expectedAttempts:    []int{2},
+		},
+		{
+			name:        "max_attempts",
+			retryPolicy: retryPolicy,
+			responses: []dstest.Response{
+				{StatusCode: http.StatusInternalServerError},
+				{StatusCode: http.StatusBadGateway},
+				{StatusCode: http.StatusServiceUnavailable},
+			},
+			expectedStatusCode:  http.StatusServiceUnavailable,
+			expectedStatusCodes: []int{http.StatusInternalServerError, http.StatusBadGateway, http.StatusServiceUnavailable},
+			expectedAttempts:    []int{1, 2, 3},
+		},
+		{
+			name:        "not_transient",
+			retryPolicy: retryPolicy,
+			responses: []dstest.Response{
+				{StatusCode: http.StatusBadRequest},
+			},
+			expectedStatusCode:  http.StatusBadRequest,
+			expectedStatusCodes: []int{http.StatusBadRequest},
+			expectedAttempts:    []int{1},
+		},
+		{
+			name:        "retry_after_exceeds_deadline",
+			retryPolicy: retryPolicy,
+			responses: []dstest.Response{
+				{StatusCode: http.StatusTooManyRequests, Header: http.Header{"Retry-After": []string{"3600"}}},
+			},
+			timeout:             time.Minute,
+			expectedStatusCode:  http.StatusTooManyRequests,
+			expectedStatusCodes: []int{http.StatusTooManyRequests},
+			expectedAttempts:    []int{1},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			s := dstest.NewServer(
+				dstest.WithDefaultForecasts(),
+				dstest.WithResponses(tc.responses...),
+			)
+			defer s.Close()
+			var statusCodes []int
+			var attempts []int
+			c, err := s.NewClient(
+				darksky.WithRetryPolicy(tc.retryPolicy),
+				darksky.WithResponseMetadataCallback(func(rm *darksky.ResponseMetadata) {
+					statusCodes = append(statusCodes, rm.StatusCode)
+					attempts = append(attempts, rm.Attempt)
+				}),
+			)
+			require.NoError(t, err)
+
+			ctx := context.Background()
+			if tc.timeout != 0 {
+				var cancel context.CancelFunc
+				ctx, cancel = context.WithTimeout(ctx, tc.timeout)
+				defer cancel()
+			}
+			f, err := c.Forecast(ctx, 34.0219, -118.4814, &darksky.Time{Time: time.Date(2019, 5, 1, 0, 0, 0, 0, time.UTC)}, nil)
+			if tc.expectedStatusCode != 0 {
+				require.Error(t, err)
+				e, ok := err.(*darksky.Error)
+				require.True(t, ok)
+				assert.Equal(t, tc.expectedStatusCode, e.Response.StatusCode)
+			} else {
+				require.NoError(t, err)
+				assert.Equal(t, "America/Los_Angeles", f.Timezone)
+			}
+			assert.Equal(t, tc.expectedStatusCodes, statusCodes)
+			assert.Equal(t, tc.expectedAttempts, attempts)
+		})
+	}
+}
+
+func TestClientRetryPolicyContextCanceled(t *testing.T) {
+	s := dstest.NewServer(
+		dstest.WithDefaultForecasts(),
+		dstest.WithResponses(dstest.Response{StatusCode: http.StatusServiceUnavailable}),
+	)
+	defer s.Close()
+	c, err := s.NewClient(
+		darksky.WithRetryPolicy(&darksky.RetryPolicy{
+			MaxAttempts:    2,
+			InitialBackoff: time.Hour,
+		}),
+	)
+	require.NoError(t, err)
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(10*time.Millisecond, cancel)
+	_, err = c.Forecast(ctx, 34.0219, -118.4814, &darksky.Time{Time: time.Date(2019, 5, 1, 0, 0, 0, 0, time.UTC)}, nil)
+	assert.Equal(t, context.Canceled, err)
+}
+
+func TestWithRetryPolicyInvalid(t *testing.T) {
+	for _, tc := range []struct {
+		name        string
+		retryPolicy *darksky.RetryPolicy
+		expectErr   bool
+	}{
+		{
+			name:        "default",
+			retryPolicy: darksky.DefaultRetryPolicy(),
+		},
+		{
+			name:        "zero",
+			retryPolicy: &darksky.RetryPolicy{},
+		},
+		{
+			name:        "negative_initial_backoff",
+			retryPolicy: &darksky.RetryPolicy{InitialBackoff: -time.Second},
+			expectErr:   true,
+		},
+		{
+			name:        "negative_max_backoff",
+			retryPolicy: &darksky.RetryPolicy{MaxBackoff: -time.Second},
+			expectErr:   true,
+		},
+		{
+			name:        "negative_multiplier",
+			retryPolicy: &darksky.RetryPolicy{Multiplier: -1},
+			expectErr:   true,
+		},
+		{
+			name:        "nan_multiplier",
+			retryPolicy: &darksky.RetryPolicy{Multiplier: math.NaN()},
+			expectErr:   true,
+		},
+		{
+			name:        "negative_jitter",
+			retryPolicy: &darksky.RetryPolicy{Jitter: -0.5},
+			expectErr:   true,
+		},
+		{
+			name:        "jitter_too_large",
+			retryPolicy: &darksky.RetryPolicy{Jitter: 1.5},
+			expectErr:   true,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := darksky.NewClient(darksky.WithRetryPolicy(tc.retryPolicy))
+			if tc.expectErr {
+				assert.True(t, errors.Is(err, darksky.ErrInvalidRetryPolicy))
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestDefaultRetryPolicy(t *testing.T) {
+	rp := darksky.DefaultRetryPolicy()
+	rp.MaxAttempts = 1
+	assert.NotEqual(t, rp, darksky.DefaultRetryPolicy(), "modifying the returned policy should not modify the default")
+}
@@ -0,0 +1,126 @@
+package darksky
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// A flightGroup coalesces concurrent calls with the same key into a single
+// call.
+type flightGroup struct {
+	mutex sync.Mutex
+	calls map[string]*flightCall
+}
+
+// A flightCall is an in-flight call.
+type flightCall struct {
+	done      chan struct{}
+	cancel    context.CancelFunc
+	deadlines []time.Time // The deadlines of the waiting callers, zero if none.
+	body      []byte
+	err       error
+}
+
+// A deadlineFuncKey is the context key of a function that returns the deadline
+// of a coalesced call.
+type deadlineFuncKey struct{}
+
+// do calls fn and returns its results, unless a call with the same key is
+// already in flight, in which case it waits for and returns the results of
+// that call.
+//
+// fn is called in its own goroutine with a context that is not tied to any
+// single caller, so one caller's ctx being done does not fail the other
+// callers. fn's context is canceled when every caller's ctx is done, and its
+// deadline, as returned by contextDeadline, is the latest of the waiting
+// callers' deadlines. Each caller returns ctx.Err() as soon as its own ctx is
+// done.
+func (g *flightGroup) do(ctx context.Context, key string, fn func(context.Context) ([]byte, error)) ([]byte, error) {
+	deadline, _ := ctx.Deadline()
+
+	g.mutex.Lock()
+	if g.calls == nil {
+		g.calls = make(map[string]*flightCall)
+	}
+	call, ok := g.calls[key]
+	if !ok {
+		callCtx, cancel := context.WithCancel(context.Background())
+		call = &flightCall{
+			done:   make(chan struct{}),
+			cancel: cancel,
+		}
+		callCtx = context.WithValue(callCtx, deadlineFuncKey{}, func() (time.Time, bool) {
+			return g.deadline(call)
+		})
+		g.calls[key] = call
+		go func() {
+			call.body, call.err = fn(callCtx)
+			g.forget(key, call)
+			cancel()
+			close(call.done)
+		}()
+	}
+	call.deadlines = append(call.deadlines, deadline)
+	g.mutex.Unlock()
+
+	select {
+	case <-call.done:
+		return call.body, call.err
+	case <-ctx.Done():
+		g.mutex.Lock()
+		for i, d := range call.deadlines {
+			if d.Equal(deadline) {
+				call.deadlines = append(call.deadlines[:i], call.deadlines[i+1:]...)
+				break
+			}
+		}
+		if len(call.deadlines) == 0 {
+			call.cancel()
+			g.forgetLocked(key, call)
+		}
+		g.mutex.Unlock()
+		return nil, ctx.Err()
+	}
+}
+
+// deadline returns the latest deadline of call's waiting callers. There is no
+// deadline if any waiting caller has no deadline.
+func (g *flightGroup) deadline(call *flightCall) (time.Time, bool) {
+	g.mutex.Lock()
+	defer g.mutex.Unlock()
+	var latest time.Time
+	for _, d := range call.deadlines {
+		if d.IsZero() {
+			return time.Time{}, false
+		}
+		if d.After(latest) {
+			latest = d
+		}
+	}
+	return latest, !latest.IsZero()
+}
+
+// forget removes call from g, so that later calls with key start a new call.
+func (g *flightGroup) forget(key string, call *flightCall) {
+	g.mutex.Lock()
+	defer g.mutex.Unlock()
+	g.forgetLocked(key, call)
+}
+
+// forgetLocked is like forget but requires g.mutex to be held.
+func (g *flightGroup) forgetLocked(key string, call *flightCall) {
+	if g.calls[key] == call {
+		delete(g.calls, key)
+	}
+}
+
+// contextDeadline returns the deadline of ctx. If ctx is the context of a
+// coalesced call then this is the latest deadline of the call's waiting
+// callers.
+func contextDeadline(ctx context.Context) (time.Time, bool) {
+	if f, ok := ctx.Value(deadlineFuncKey{}).(func() (time.Time, bool)); ok {
+		return f()
+	}
+	return ctx.Deadline()
+}
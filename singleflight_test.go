@@ -0,0 +1,187 @@
+package darksky
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClientCoalesceRequests(t *testing.T) {
+	const n = 8
+
+	var requests int32
+	started := make(chan struct{})
+	release := make(chan struct{})
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&requests, 1) == 1 {
+			close(started)
+		}
+		<-release
+		_, _ = w.Write([]byte(`{"latitude":42.3601,"longitude":-71.0589,"alerts":[{"title":"Flood Warning"}]}`))
+	}))
+	defer s.Close()
+
+	var callbacks int32
+	c, err := NewClient(
+		WithBaseURL(s.URL),
+		WithHTTPClient(s.Client()),
+		WithKey("key"),
+		WithResponseMetadataCallback(func(*ResponseMetadata) {
+			atomic.AddInt32(&callbacks, 1)
+		}),
+	)
+	require.NoError(t, err)
+
+	forecasts := make([]*Forecast, n)
+	errs := make([]error, n)
+	var wg sync.WaitGroup
+	forecast := func(i int) {
+		defer wg.Done()
+		forecasts[i], errs[i] = c.Forecast(context.Background(), 42.3601, -71.0589, nil, nil)
+	}
+	wg.Add(n)
+	go forecast(0)
+	<-started
+	for i := 1; i < n; i++ {
+		go forecast(i)
+	}
+	key := forecastCacheKey(42.3601, -71.0589, nil, nil)
+	for deadline := time.Now().Add(time.Second); ; {
+		c.flights.mutex.Lock()
+		callers := len(c.flights.calls[key].deadlines)
+		c.flights.mutex.Unlock()
+		if callers == n {
+			break
+		}
+		require.True(t, time.Now().Before(deadline), "timed out waiting for coalesced calls")
+		time.Sleep(time.Millisecond)
+	}
+	close(release)
+	wg.Wait()
+
+	assert.Equal(t, int32(1), atomic.LoadInt32(&requests))
+	assert.Equal(t, int32(1), atomic.LoadInt32(&callbacks))
+	for i := 0; i < n; i++ {
+		require.NoError(t, errs[i])
+		require.Len(t, forecasts[i].Alerts, 1)
+	}
+	forecasts[0].Alerts[0].Title = "modified"
+	for i := 1; i < n; i++ {
+		assert.True(t, forecasts[0] != forecasts[i])
+		assert.Equal(t, "Flood Warning", forecasts[i].Alerts[0].Title)
+	}
+}
+
+func TestClientCoalesceRequestsCancel(t *testing.T) {
+	key := forecastCacheKey(42.3601, -71.0589, nil, nil)
+
+	// newClient returns a new client for a server that blocks until release is
+	// closed or the request is canceled.
+	newClient := func(t *testing.T) (c *Client, started, release, canceled chan struct{}, closeServer func()) {
+		started = make(chan struct{})
+		release = make(chan struct{})
+		canceled = make(chan struct{})
+		s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			close(started)
+			select {
+			case <-release:
+				_, _ = w.Write([]byte(`{"latitude":42.3601,"longitude":-71.0589}`))
+			case <-r.Context().Done():
+				close(canceled)
+			}
+		}))
+		c, err := NewClient(
+			WithBaseURL(s.URL),
+			WithHTTPClient(s.Client()),
+			WithKey("key"),
+		)
+		require.NoError(t, err)
+		return c, started, release, canceled, s.Close
+	}
+
+	waitForCallers := func(t *testing.T, c *Client, callers int) {
+		for deadline := time.Now().Add(time.Second); ; {
+			c.flights.mutex.Lock()
+			call := c.flights.calls[key]
+			ok := call != nil && len(call.deadlines) == callers
+			c.flights.mutex.Unlock()
+			if ok {
+				return
+			}
+			require.True(t, time.Now().Before(deadline), "timed out waiting for coalesced calls")
+			time.Sleep(time.Millisecond)
+		}
+	}
+
+	t.Run("leader_canceled", func(t *testing.T) {
+		c, started, release, _, closeServer := newClient(t)
+		defer closeServer()
+
+		leaderCtx, cancelLeader := context.WithCancel(context.Background())
+		leaderErr := make(chan error)
+		go func() {
+			_, err := c.Forecast(leaderCtx, 42.3601, -71.0589, nil, nil)
+			leaderErr <- err
+		}()
+		<-started
+		type result struct {
+			forecast *Forecast
+			err      error
+		}
+		followerResult := make(chan result)
+		go func() {
+			forecast, err := c.Forecast(context.Background(), 42.3601, -71.0589, nil, nil)
+			followerResult <- result{forecast: forecast, err: err}
+		}()
+		waitForCallers(t, c, 2)
+
+		cancelLeader()
+		assert.Equal(t, context.Canceled, <-leaderErr)
+		close(release)
+		r := <-followerResult
+		require.NoError(t, r.err)
+		assert.Equal(t, 42.3601, r.forecast.Latitude)
+	})
+
+	t.Run("all_canceled", func(t *testing.T) {
+		c, started, release, canceled, closeServer := newClient(t)
+		defer closeServer()
+		defer close(release)
+
+		ctx1, cancel1 := context.WithCancel(context.Background())
+		ctx2, cancel2 := context.WithCancel(context.Background())
+		errs := make(chan error)
+		go func() {
+			_, err := c.Forecast(ctx1, 42.3601, -71.0589, nil, nil)
+			errs <- err
+		}()
+		<-started
+		go func() {
+			_, err := c.Forecast(ctx2, 42.3601, -71.0589, nil, nil)
+			errs <- err
+		}()
+		waitForCallers(t, c, 2)
+
+		cancel1()
+		assert.Equal(t, context.Canceled, <-errs)
+		select {
+		case <-canceled:
+			require.FailNow(t, "request canceled while a caller was waiting")
+		case <-time.After(10 * time.Millisecond):
+		}
+		cancel2()
+		assert.Equal(t, context.Canceled, <-errs)
+		select {
+		case <-canceled:
+		case <-time.After(time.Second):
+			require.FailNow(t, "timed out waiting for request to be canceled")
+		}
+	})
+}
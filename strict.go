@@ -0,0 +1,136 @@
+package darksky
+
+import (
+	"encoding/json"
+	"io"
+	"io/ioutil"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// An UnknownField is a field in a response that is not modeled by Forecast.
+// Path is the path to the field, for example "hourly.data[].newField". Value
+// is the field's value in its first occurrence.
+type UnknownField struct {
+	Path  string
+	Value json.RawMessage
+}
+
+// WithStrictDecoding enables strict decoding. When enabled, fields in
+// responses that are not modeled by Forecast are reported in the returned
+// Forecast's UnknownFields. Unknown fields do not cause requests to fail.
+func WithStrictDecoding() ClientOption {
+	return func(c *Client) {
+		c.strictDecoding = true
+	}
+}
+
+// ParseForecast parses a Forecast from r. If strict is true then fields that
+// are not modeled by Forecast are reported in the returned Forecast's
+// UnknownFields.
+func ParseForecast(r io.Reader, strict bool) (*Forecast, error) {
+	data, err := ioutil.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	return parseForecast(data, strict)
+}
+
+func parseForecast(data []byte, strict bool) (*Forecast, error) {
+	forecast := &Forecast{}
+	if err := json.Unmarshal(data, forecast); err != nil {
+		return forecast, err
+	}
+	forecast.location = loadLocation(forecast.Timezone, forecast.Offset)
+	setTimeLocations(reflect.ValueOf(forecast), forecast.location)
+	if strict {
+		var v interface{}
+		if err := json.Unmarshal(data, &v); err != nil {
+			return forecast, err
+		}
+		seen := make(map[string]bool)
+		forecast.UnknownFields = appendUnknownFields(nil, seen, "", v, reflect.TypeOf(forecast))
+	}
+	return forecast, nil
+}
+
+// appendUnknownFields appends the fields in v, which is at path, that are not
+// modeled by t to unknownFields. Only the first occurrence of each path is
+// appended.
+func appendUnknownFields(unknownFields []*UnknownField, seen map[string]bool, path string, v interface{}, t reflect.Type) []*UnknownField {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	switch t.Kind() {
+	case reflect.Slice:
+		if elems, ok := v.([]interface{}); ok {
+			for _, elem := range elems {
+				unknownFields = appendUnknownFields(unknownFields, seen, path+"[]", elem, t.Elem())
+			}
+		}
+	case reflect.Struct:
+		object, ok := v.(map[string]interface{})
+		if !ok {
+			return unknownFields
+		}
+		fields := jsonFields(t)
+		keys := make([]string, 0, len(object))
+		for key := range object {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			fieldPath := key
+			if path != "" {
+				fieldPath = path + "." + key
+			}
+			field, ok := fields[key]
+			if !ok {
+				field, ok = fields[strings.ToLower(key)]
+			}
+			if ok {
+				unknownFields = appendUnknownFields(unknownFields, seen, fieldPath, object[key], field.Type)
+				continue
+			}
+			if seen[fieldPath] {
+				continue
+			}
+			seen[fieldPath] = true
+			value, _ := json.Marshal(object[key])
+			unknownFields = append(unknownFields, &UnknownField{
+				Path:  fieldPath,
+				Value: value,
+			})
+		}
+	}
+	return unknownFields
+}
+
+// jsonFields returns the fields of struct type t, indexed by JSON name and by
+// lowercase JSON name, as encoding/json matches names case-insensitively.
+func jsonFields(t reflect.Type) map[string]reflect.StructField {
+	fields := make(map[string]reflect.StructField)
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if field.PkgPath != "" {
+			continue
+		}
+		name := field.Name
+		if tag := field.Tag.Get("json"); tag != "" {
+			if tag == "-" {
+				continue
+			}
+			if tagName := strings.Split(tag, ",")[0]; tagName != "" {
+				name = tagName
+			}
+		}
+		fields[name] = field
+		if lowerName := strings.ToLower(name); lowerName != name {
+			if _, ok := fields[lowerName]; !ok {
+				fields[lowerName] = field
+			}
+		}
+	}
+	return fields
+}
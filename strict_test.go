@@ -0,0 +1,79 @@
+package darksky
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseForecast(t *testing.T) {
+	for _, tc := range []struct {
+		name                  string
+		data                  string
+		strict                bool
+		expectedUnknownFields []*UnknownField
+	}{
+		{
+			name:   "known_fields",
+			data:   `{"latitude":1,"Longitude":2,"currently":{"temperature":3},"hourly":{"data":[{"time":0}]},"flags":{"darksky-unavailable":{"x":1}}}`,
+			strict: true,
+		},
+		{
+			name: "not_strict",
+			data: `{"latitude":1,"newField":true}`,
+		},
+		{
+			name:   "unknown_fields",
+			data:   `{"latitude":1,"newField":true,"currently":{"newCurrently":"x"},"hourly":{"data":[{"newHourly":1},{"newHourly":2}]},"alerts":[{"newAlert":[1]}]}`,
+			strict: true,
+			expectedUnknownFields: []*UnknownField{
+				{Path: "alerts[].newAlert", Value: json.RawMessage(`[1]`)},
+				{Path: "currently.newCurrently", Value: json.RawMessage(`"x"`)},
+				{Path: "hourly.data[].newHourly", Value: json.RawMessage(`1`)},
+				{Path: "newField", Value: json.RawMessage(`true`)},
+			},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			forecast, err := ParseForecast(strings.NewReader(tc.data), tc.strict)
+			require.NoError(t, err)
+			assert.Equal(t, 1.0, forecast.Latitude)
+			assert.Equal(t, tc.expectedUnknownFields, forecast.UnknownFields)
+		})
+	}
+}
+
+func TestParseForecastComplete(t *testing.T) {
+	f, err := os.Open("testdata/complete.json")
+	require.NoError(t, err)
+	defer f.Close()
+	forecast, err := ParseForecast(f, true)
+	require.NoError(t, err)
+	assert.Empty(t, forecast.UnknownFields)
+}
+
+func TestClientStrictDecoding(t *testing.T) {
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"latitude":42.3601,"longitude":-71.0589,"newField":1}`))
+	}))
+	defer s.Close()
+	c, err := NewClient(
+		WithBaseURL(s.URL),
+		WithHTTPClient(s.Client()),
+		WithStrictDecoding(),
+	)
+	require.NoError(t, err)
+	forecast, err := c.Forecast(context.Background(), 42.3601, -71.0589, nil, nil)
+	require.NoError(t, err)
+	assert.Equal(t, 42.3601, forecast.Latitude)
+	assert.Equal(t, []*UnknownField{
+		{Path: "newField", Value: json.RawMessage(`1`)},
+	}, forecast.UnknownFields)
+}
@@ -0,0 +1,86 @@
+package darksky
+
+import (
+	"fmt"
+	"math"
+	"strings"
+
+	"golang.org/x/text/message"
+)
+
+// iconConditions are the English conditions described by icons.
+var iconConditions = map[Icon]string{
+	IconClearDay:          "Clear",
+	IconClearNight:        "Clear",
+	IconCloudy:            "Cloudy",
+	IconFog:               "Fog",
+	IconPartlyCloudyDay:   "Partly cloudy",
+	IconPartlyCloudyNight: "Partly cloudy",
+	IconRain:              "Rain",
+	IconSleet:             "Sleet",
+	IconSnow:              "Snow",
+	IconWind:              "Windy",
+}
+
+// precipChanceFormats are the English format strings for precipitation chances.
+var precipChanceFormats = map[PrecipType]string{
+	PrecipTypeRain:  "%d%% chance of rain",
+	PrecipTypeSleet: "%d%% chance of sleet",
+	PrecipTypeSnow:  "%d%% chance of snow",
+}
+
+// GenerateSummary returns a summary of d generated from its icon,
+// temperature, and precipitation probability, for example "Partly cloudy,
+// 72°F, 30% chance of rain.", in lang. d's values are in units. Languages other
+// than SummaryLangs are summarized in English. Unlike d.Summary, the summary
+// can be generated in any language without a new request.
+func (d *HourlyData) GenerateSummary(lang Lang, units Units) (string, error) {
+	unitSystem, ok := unitSystems[units]
+	if !ok {
+		return "", fmt.Errorf("%w: %q", ErrUnsupportedUnits, units)
+	}
+	p := newSummaryPrinter(lang)
+	temperature := p.Sprintf("%d", int(math.Round(d.Temperature))) + temperatureSymbol(unitSystem)
+	return generateSummary(p, d.Icon, temperature, d.PrecipProbability, d.PrecipType), nil
+}
+
+// GenerateSummary returns a summary of d generated from its icon, minimum and
+// maximum temperatures, and precipitation probability, for example "Partly
+// cloudy, 55–72°F, 30% chance of rain.", in lang. d's values are in units.
+// Languages other than SummaryLangs are summarized in English. Unlike
+// d.Summary, the summary can be generated in any language without a new
+// request.
+func (d *DailyData) GenerateSummary(lang Lang, units Units) (string, error) {
+	unitSystem, ok := unitSystems[units]
+	if !ok {
+		return "", fmt.Errorf("%w: %q", ErrUnsupportedUnits, units)
+	}
+	p := newSummaryPrinter(lang)
+	temperature := p.Sprintf("%d–%d", int(math.Round(d.TemperatureMin)), int(math.Round(d.TemperatureMax))) + temperatureSymbol(unitSystem)
+	return generateSummary(p, d.Icon, temperature, d.PrecipProbability, d.PrecipType), nil
+}
+
+// generateSummary joins the condition described by icon, temperature, and the
+// chance of precipitation, rounded to the nearest 10%, if it is non-zero.
+func generateSummary(p *message.Printer, icon Icon, temperature string, precipProbability float64, precipType PrecipType) string {
+	var parts []string
+	if condition, ok := iconConditions[icon]; ok {
+		parts = append(parts, p.Sprintf(condition))
+	}
+	parts = append(parts, temperature)
+	if percent := 10 * int(math.Round(10*precipProbability)); percent > 0 {
+		format, ok := precipChanceFormats[precipType]
+		if !ok {
+			format = "%d%% chance of precipitation"
+		}
+		parts = append(parts, p.Sprintf(format, percent))
+	}
+	return strings.Join(parts, ", ") + "."
+}
+
+func temperatureSymbol(unitSystem unitSystem) string {
+	if unitSystem.fahrenheit {
+		return "°F"
+	}
+	return "°C"
+}
@@ -0,0 +1,116 @@
+package darksky_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/twpayne/go-darksky"
+)
+
+func TestHourlyDataGenerateSummary(t *testing.T) {
+	for _, tc := range []struct {
+		name     string
+		d        *darksky.HourlyData
+		lang     darksky.Lang
+		units    darksky.Units
+		expected string
+	}{
+		{
+			name: "en_us",
+			d: &darksky.HourlyData{
+				Icon:              darksky.IconPartlyCloudyDay,
+				PrecipProbability: 0.27,
+				PrecipType:        darksky.PrecipTypeRain,
+				Temperature:       71.6,
+			},
+			lang:     darksky.LangEN,
+			units:    darksky.UnitsUS,
+			expected: "Partly cloudy, 72°F, 30% chance of rain.",
+		},
+		{
+			name: "en_si_dry",
+			d: &darksky.HourlyData{
+				Icon:              darksky.IconClearNight,
+				PrecipProbability: 0.04,
+				Temperature:       -3.2,
+			},
+			lang:     darksky.LangEN,
+			units:    darksky.UnitsSI,
+			expected: "Clear, -3°C.",
+		},
+		{
+			name: "de",
+			d: &darksky.HourlyData{
+				Icon:              darksky.IconSnow,
+				PrecipProbability: 0.8,
+				PrecipType:        darksky.PrecipTypeSnow,
+				Temperature:       -1,
+			},
+			lang:     darksky.LangDE,
+			units:    darksky.UnitsCA,
+			expected: "Schnee, -1°C, 80% Schneewahrscheinlichkeit.",
+		},
+		{
+			name: "fr_unknown_icon",
+			d: &darksky.HourlyData{
+				Icon:              darksky.Icon("tornado"),
+				PrecipProbability: 0.5,
+				Temperature:       20,
+			},
+			lang:     darksky.LangFR,
+			units:    darksky.UnitsSI,
+			expected: "20°C, 50 % de risque de précipitations.",
+		},
+		{
+			name: "unsupported_lang",
+			d: &darksky.HourlyData{
+				Icon:        darksky.IconWind,
+				Temperature: 60,
+			},
+			lang:     darksky.LangJA,
+			units:    darksky.UnitsUS,
+			expected: "Windy, 60°F.",
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			actual, err := tc.d.GenerateSummary(tc.lang, tc.units)
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, actual)
+		})
+	}
+
+	_, err := (&darksky.HourlyData{}).GenerateSummary(darksky.LangEN, darksky.Units("imperial"))
+	assert.True(t, errors.Is(err, darksky.ErrUnsupportedUnits))
+}
+
+func TestDailyDataGenerateSummary(t *testing.T) {
+	d := &darksky.DailyData{
+		Icon:              darksky.IconRain,
+		PrecipProbability: 0.62,
+		PrecipType:        darksky.PrecipTypeRain,
+		TemperatureMax:    16.7,
+		TemperatureMin:    9.4,
+	}
+	for lang, expected := range map[darksky.Lang]string{
+		darksky.LangEN: "Rain, 9–17°C, 60% chance of rain.",
+		darksky.LangES: "Lluvia, 9–17°C, 60% de probabilidad de lluvia.",
+		darksky.LangNL: "Regen, 9–17°C, 60% kans op regen.",
+	} {
+		actual, err := d.GenerateSummary(lang, darksky.UnitsSI)
+		require.NoError(t, err)
+		assert.Equal(t, expected, actual, lang)
+	}
+
+	_, err := d.GenerateSummary(darksky.LangEN, darksky.Units("imperial"))
+	assert.True(t, errors.Is(err, darksky.ErrUnsupportedUnits))
+}
+
+func TestSummaryLangs(t *testing.T) {
+	summaryLangs := darksky.SummaryLangs()
+	assert.Contains(t, summaryLangs, darksky.LangEN)
+	summaryLangs[0] = darksky.LangJA
+	assert.NotContains(t, darksky.SummaryLangs(), darksky.LangJA)
+}
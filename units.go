@@ -0,0 +1,176 @@
+package darksky
+
+import (
+	"errors"
+	"fmt"
+)
+
+var (
+	// ErrNoFlags is returned by Forecast.ConvertUnits when the forecast has no
+	// flags, and so its units are unknown.
+	ErrNoFlags = errors.New("darksky: no flags")
+
+	// ErrUnsupportedUnits is returned when converting from or to units that
+	// are not supported, for example UnitsAuto.
+	ErrUnsupportedUnits = errors.New("darksky: unsupported units")
+)
+
+// A unitSystem describes the units of a Units. Factors convert to SI units.
+type unitSystem struct {
+	fahrenheit         bool    // Temperatures are in °F, otherwise °C.
+	distance           float64 // Kilometers per distance unit.
+	speed              float64 // Meters per second per speed unit.
+	precipIntensity    float64 // Millimeters per hour per precipitation intensity unit.
+	precipAccumulation float64 // Centimeters per precipitation accumulation unit.
+}
+
+// unitSystems are the unit systems, see https://darksky.net/dev/docs.
+var unitSystems = map[Units]unitSystem{
+	UnitsCA: {
+		distance:           1,
+		speed:              1 / 3.6,
+		precipIntensity:    1,
+		precipAccumulation: 1,
+	},
+	UnitsSI: {
+		distance:           1,
+		speed:              1,
+		precipIntensity:    1,
+		precipAccumulation: 1,
+	},
+	UnitsUK2: {
+		distance:           1.609344,
+		speed:              0.44704,
+		precipIntensity:    1,
+		precipAccumulation: 1,
+	},
+	UnitsUS: {
+		fahrenheit:         true,
+		distance:           1.609344,
+		speed:              0.44704,
+		precipIntensity:    25.4,
+		precipAccumulation: 2.54,
+	},
+}
+
+// A unitConverter converts values between two unit systems.
+type unitConverter struct {
+	from, to unitSystem
+}
+
+// ConvertUnits converts all values in f to units to and updates f.Flags.Units.
+// Pressures are in millibars, which are equal to hectopascals, in all systems,
+// so they are unchanged.
+func (f *Forecast) ConvertUnits(to Units) error {
+	if f.Flags == nil {
+		return fmt.Errorf("%w: cannot convert units", ErrNoFlags)
+	}
+	uc, err := newUnitConverter(f.Flags.Units, to)
+	if err != nil {
+		return err
+	}
+	if f.Currently != nil {
+		f.Currently.ApparentTemperature = uc.temperature(f.Currently.ApparentTemperature)
+		f.Currently.DewPoint = uc.temperature(f.Currently.DewPoint)
+		f.Currently.NearestStormDistance = uc.distance(f.Currently.NearestStormDistance)
+		f.Currently.PrecipAccumulation = uc.precipAccumulation(f.Currently.PrecipAccumulation)
+		f.Currently.PrecipIntensity = uc.precipIntensity(f.Currently.PrecipIntensity)
+		f.Currently.PrecipIntensityError = uc.precipIntensity(f.Currently.PrecipIntensityError)
+		f.Currently.Temperature = uc.temperature(f.Currently.Temperature)
+		f.Currently.Visibility = uc.distance(f.Currently.Visibility)
+		f.Currently.WindGust = uc.speed(f.Currently.WindGust)
+		f.Currently.WindSpeed = uc.speed(f.Currently.WindSpeed)
+	}
+	if f.Daily != nil {
+		for _, d := range f.Daily.Data {
+			if d == nil {
+				continue
+			}
+			d.ApparentTemperatureHigh = uc.temperature(d.ApparentTemperatureHigh)
+			d.ApparentTemperatureLow = uc.temperature(d.ApparentTemperatureLow)
+			d.ApparentTemperatureMax = uc.temperature(d.ApparentTemperatureMax)
+			d.ApparentTemperatureMin = uc.temperature(d.ApparentTemperatureMin)
+			d.DewPoint = uc.temperature(d.DewPoint)
+			d.PrecipAccumulation = uc.precipAccumulation(d.PrecipAccumulation)
+			d.PrecipIntensity = uc.precipIntensity(d.PrecipIntensity)
+			d.PrecipIntensityError = uc.precipIntensity(d.PrecipIntensityError)
+			d.PrecipIntensityMax = uc.precipIntensity(d.PrecipIntensityMax)
+			d.TemperatureHigh = uc.temperature(d.TemperatureHigh)
+			d.TemperatureLow = uc.temperature(d.TemperatureLow)
+			d.TemperatureMax = uc.temperature(d.TemperatureMax)
+			d.TemperatureMin = uc.temperature(d.TemperatureMin)
+			d.Visibility = uc.distance(d.Visibility)
+			d.WindGust = uc.speed(d.WindGust)
+			d.WindSpeed = uc.speed(d.WindSpeed)
+		}
+	}
+	if f.Hourly != nil {
+		for _, h := range f.Hourly.Data {
+			if h == nil {
+				continue
+			}
+			h.ApparentTemperature = uc.temperature(h.ApparentTemperature)
+			h.DewPoint = uc.temperature(h.DewPoint)
+			h.PrecipAccumulation = uc.precipAccumulation(h.PrecipAccumulation)
+			h.PrecipIntensity = uc.precipIntensity(h.PrecipIntensity)
+			h.PrecipIntensityError = uc.precipIntensity(h.PrecipIntensityError)
+			h.Temperature = uc.temperature(h.Temperature)
+			h.Visibility = uc.distance(h.Visibility)
+			h.WindGust = uc.speed(h.WindGust)
+			h.WindSpeed = uc.speed(h.WindSpeed)
+		}
+	}
+	if f.Minutely != nil {
+		for _, m := range f.Minutely.Data {
+			if m == nil {
+				continue
+			}
+			m.PrecipIntensity = uc.precipIntensity(m.PrecipIntensity)
+			m.PrecipIntensityError = uc.precipIntensity(m.PrecipIntensityError)
+		}
+	}
+	f.Flags.Units = to
+	return nil
+}
+
+func newUnitConverter(from, to Units) (*unitConverter, error) {
+	fromUnitSystem, ok := unitSystems[from]
+	if !ok {
+		return nil, fmt.Errorf("%w: %q", ErrUnsupportedUnits, from)
+	}
+	toUnitSystem, ok := unitSystems[to]
+	if !ok {
+		return nil, fmt.Errorf("%w: %q", ErrUnsupportedUnits, to)
+	}
+	return &unitConverter{
+		from: fromUnitSystem,
+		to:   toUnitSystem,
+	}, nil
+}
+
+func (uc *unitConverter) distance(x float64) float64 {
+	return x * uc.from.distance / uc.to.distance
+}
+
+func (uc *unitConverter) precipAccumulation(x float64) float64 {
+	return x * uc.from.precipAccumulation / uc.to.precipAccumulation
+}
+
+func (uc *unitConverter) precipIntensity(x float64) float64 {
+	return x * uc.from.precipIntensity / uc.to.precipIntensity
+}
+
+func (uc *unitConverter) speed(x float64) float64 {
+	return x * uc.from.speed / uc.to.speed
+}
+
+func (uc *unitConverter) temperature(x float64) float64 {
+	switch {
+	case uc.from.fahrenheit && !uc.to.fahrenheit:
+		return (x - 32) * 5 / 9
+	case !uc.from.fahrenheit && uc.to.fahrenheit:
+		return x*9/5 + 32
+	default:
+		return x
+	}
+}
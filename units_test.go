@@ -0,0 +1,165 @@
+package darksky_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/twpayne/go-darksky"
+	"github.com/twpayne/go-darksky/dstest"
+)
+
+func TestForecastConvertUnits(t *testing.T) {
+	for _, tc := range []struct {
+		name      string
+		from      darksky.Units
+		to        darksky.Units
+		currently darksky.Currently
+		expected  darksky.Currently
+	}{
+		{
+			name: "us_to_si",
+			from: darksky.UnitsUS,
+			to:   darksky.UnitsSI,
+			currently: darksky.Currently{
+				Temperature:          50,
+				DewPoint:             32,
+				NearestStormDistance: 10,
+				PrecipAccumulation:   1,
+				PrecipIntensity:      1,
+				Pressure:             1013,
+				WindSpeed:            10,
+			},
+			expected: darksky.Currently{
+				Temperature:          10,
+				NearestStormDistance: 16.09344,
+				PrecipAccumulation:   2.54,
+				PrecipIntensity:      25.4,
+				Pressure:             1013,
+				WindSpeed:            4.4704,
+			},
+		},
+		{
+			name: "si_to_ca",
+			from: darksky.UnitsSI,
+			to:   darksky.UnitsCA,
+			currently: darksky.Currently{
+				Temperature:          -40,
+				NearestStormDistance: 10,
+				PrecipIntensity:      1,
+				WindSpeed:            10,
+			},
+			expected: darksky.Currently{
+				Temperature:          -40,
+				NearestStormDistance: 10,
+				PrecipIntensity:      1,
+				WindSpeed:            36,
+			},
+		},
+		{
+			name: "ca_to_uk2",
+			from: darksky.UnitsCA,
+			to:   darksky.UnitsUK2,
+			currently: darksky.Currently{
+				Temperature: 20,
+				Visibility:  16.09344,
+				WindSpeed:   16.09344,
+			},
+			expected: darksky.Currently{
+				Temperature: 20,
+				Visibility:  10,
+				WindSpeed:   10,
+			},
+		},
+		{
+			name: "uk2_to_us",
+			from: darksky.UnitsUK2,
+			to:   darksky.UnitsUS,
+			currently: darksky.Currently{
+				Temperature:     100,
+				DewPoint:        -40,
+				PrecipIntensity: 2.54,
+				Visibility:      10,
+				WindGust:        10,
+			},
+			expected: darksky.Currently{
+				Temperature:     212,
+				DewPoint:        -40,
+				PrecipIntensity: 0.1,
+				Visibility:      10,
+				WindGust:        10,
+			},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			currently := tc.currently
+			f := &darksky.Forecast{
+				Currently: &currently,
+				Flags: &darksky.Flags{
+					Units: tc.from,
+				},
+			}
+			require.NoError(t, f.ConvertUnits(tc.to))
+			assert.Equal(t, tc.to, f.Flags.Units)
+			assert.InDelta(t, tc.expected.Temperature, f.Currently.Temperature, 1e-9)
+			assert.InDelta(t, tc.expected.DewPoint, f.Currently.DewPoint, 1e-9)
+			assert.InDelta(t, tc.expected.NearestStormDistance, f.Currently.NearestStormDistance, 1e-9)
+			assert.InDelta(t, tc.expected.PrecipAccumulation, f.Currently.PrecipAccumulation, 1e-9)
+			assert.InDelta(t, tc.expected.PrecipIntensity, f.Currently.PrecipIntensity, 1e-9)
+			assert.InDelta(t, tc.expected.Pressure, f.Currently.Pressure, 1e-9)
+			assert.InDelta(t, tc.expected.Visibility, f.Currently.Visibility, 1e-9)
+			assert.InDelta(t, tc.expected.WindGust, f.Currently.WindGust, 1e-9)
+			assert.InDelta(t, tc.expected.WindSpeed, f.Currently.WindSpeed, 1e-9)
+		})
+	}
+}
+
+func TestForecastConvertUnitsRoundTrip(t *testing.T) {
+	s := dstest.NewServer(
+		dstest.WithDefaultForecasts(),
+	)
+	defer s.Close()
+	c, err := s.NewClient()
+	require.NoError(t, err)
+	options := &darksky.ForecastOptions{
+		Extend: darksky.ExtendHourly,
+		Units:  darksky.UnitsSI,
+	}
+	expected, err := c.Forecast(context.Background(), 34.0219, -118.4814, nil, options)
+	require.NoError(t, err)
+	f, err := c.Forecast(context.Background(), 34.0219, -118.4814, nil, options)
+	require.NoError(t, err)
+
+	require.NoError(t, f.ConvertUnits(darksky.UnitsUS))
+	assert.Equal(t, darksky.UnitsUS, f.Flags.Units)
+	assert.InDelta(t, expected.Currently.Temperature*9/5+32, f.Currently.Temperature, 1e-9)
+	assert.InDelta(t, expected.Daily.Data[0].TemperatureHigh*9/5+32, f.Daily.Data[0].TemperatureHigh, 1e-9)
+	assert.InDelta(t, expected.Hourly.Data[0].WindSpeed/0.44704, f.Hourly.Data[0].WindSpeed, 1e-9)
+
+	require.NoError(t, f.ConvertUnits(darksky.UnitsSI))
+	for i, h := range f.Hourly.Data {
+		assert.InDelta(t, expected.Hourly.Data[i].Temperature, h.Temperature, 1e-9)
+		assert.InDelta(t, expected.Hourly.Data[i].Visibility, h.Visibility, 1e-9)
+		assert.InDelta(t, expected.Hourly.Data[i].WindGust, h.WindGust, 1e-9)
+	}
+	for i, m := range f.Minutely.Data {
+		assert.InDelta(t, expected.Minutely.Data[i].PrecipIntensity, m.PrecipIntensity, 1e-9)
+	}
+
+	assert.True(t, errors.Is(f.ConvertUnits(darksky.UnitsAuto), darksky.ErrUnsupportedUnits))
+	assert.True(t, errors.Is((&darksky.Forecast{}).ConvertUnits(darksky.UnitsSI), darksky.ErrNoFlags))
+}
+
+func TestForecastConvertUnitsNilData(t *testing.T) {
+	f := &darksky.Forecast{
+		Daily:    &darksky.Daily{Data: []*darksky.DailyData{nil}},
+		Flags:    &darksky.Flags{Units: darksky.UnitsUS},
+		Hourly:   &darksky.Hourly{Data: []*darksky.HourlyData{nil}},
+		Minutely: &darksky.Minutely{Data: []*darksky.MinutelyData{nil}},
+	}
+	require.NoError(t, f.ConvertUnits(darksky.UnitsSI))
+	assert.Equal(t, darksky.UnitsSI, f.Flags.Units)
+}
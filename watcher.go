@@ -0,0 +1,129 @@
+package darksky
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// DefaultWatcherInterval is the default interval between polls by a Watcher.
+const DefaultWatcherInterval = 5 * time.Minute
+
+// A Clock tells the time and waits for durations to elapse.
+type Clock interface {
+	Now() time.Time
+	After(d time.Duration) <-chan time.Time
+}
+
+// realClock is a Clock that uses the time package.
+type realClock struct{}
+
+// A Watcher polls locations for alerts and invokes callbacks when alerts are
+// issued, updated, or expire. Callbacks are invoked sequentially from the
+// goroutine that calls Run.
+type Watcher struct {
+	Client    *Client
+	Locations []Location
+	Interval  time.Duration // Defaults to DefaultWatcherInterval.
+	Clock     Clock         // Defaults to the system clock.
+
+	// OnNew is called for each alert that was not present in the previous
+	// poll.
+	OnNew func(Location, *Alert)
+	// OnUpdated is called for each alert that was present in the previous
+	// poll but has changed.
+	OnUpdated func(Location, *Alert)
+	// OnExpired is called for each alert that was present in the previous poll
+	// but is no longer present or has expired.
+	OnExpired func(Location, *Alert)
+	// OnError is called when polling a location fails. The location's alerts
+	// are unchanged.
+	OnError func(Location, error)
+
+	mutex  sync.Mutex
+	alerts map[Location][]*Alert
+}
+
+// watcherExclude excludes all blocks except alerts.
+var watcherExclude = []Block{
+	BlockCurrently,
+	BlockDaily,
+	BlockFlags,
+	BlockHourly,
+	BlockMinutely,
+}
+
+func (realClock) Now() time.Time {
+	return time.Now()
+}
+
+func (realClock) After(d time.Duration) <-chan time.Time {
+	return time.After(d)
+}
+
+// Alerts returns the active alerts at location as of the last successful poll.
+func (w *Watcher) Alerts(location Location) []*Alert {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+	return append([]*Alert(nil), w.alerts[location]...)
+}
+
+// Run polls w's locations immediately and then every interval until ctx is
+// done, when it returns ctx.Err().
+func (w *Watcher) Run(ctx context.Context) error {
+	clock := w.Clock
+	if clock == nil {
+		clock = realClock{}
+	}
+	interval := w.Interval
+	if interval <= 0 {
+		interval = DefaultWatcherInterval
+	}
+	for {
+		w.poll(ctx, clock)
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-clock.After(interval):
+		}
+	}
+}
+
+// poll polls w's locations once and invokes the callbacks.
+func (w *Watcher) poll(ctx context.Context, clock Clock) {
+	results := w.Client.Forecasts(ctx, w.Locations, &ForecastOptions{
+		Exclude: watcherExclude,
+	})
+	if ctx.Err() != nil {
+		return
+	}
+	now := clock.Now()
+	for _, result := range results {
+		if result.Err != nil {
+			if w.OnError != nil {
+				w.OnError(result.Location, result.Err)
+			}
+			continue
+		}
+		alerts := DedupeAlerts(FilterAlerts(result.Forecast.Alerts, AlertActiveAt(now)))
+		w.mutex.Lock()
+		if w.alerts == nil {
+			w.alerts = make(map[Location][]*Alert)
+		}
+		diff := DiffAlerts(w.alerts[result.Location], alerts)
+		w.alerts[result.Location] = alerts
+		w.mutex.Unlock()
+		w.notify(w.OnNew, result.Location, diff.Added)
+		w.notify(w.OnUpdated, result.Location, diff.Updated)
+		w.notify(w.OnExpired, result.Location, diff.Removed)
+	}
+}
+
+func (w *Watcher) notify(f func(Location, *Alert), location Location, alerts []*Alert) {
+	if f == nil {
+		return
+	}
+	for _, a := range alerts {
+		f(location, a)
+	}
+}
@@ -0,0 +1,204 @@
+package darksky_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/twpayne/go-darksky"
+	"github.com/twpayne/go-darksky/dstest"
+)
+
+// A fakeClock is a Clock whose time is advanced by the test.
+type fakeClock struct {
+	mutex   sync.Mutex
+	now     time.Time
+	waiting chan chan time.Time
+}
+
+func newFakeClock(now time.Time) *fakeClock {
+	return &fakeClock{
+		now:     now,
+		waiting: make(chan chan time.Time),
+	}
+}
+
+func (c *fakeClock) Now() time.Time {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	return c.now
+}
+
+func (c *fakeClock) After(d time.Duration) <-chan time.Time {
+	ch := make(chan time.Time, 1)
+	c.waiting <- ch
+	return ch
+}
+
+// wait waits for the watcher to finish a poll and returns the channel that
+// will wake it.
+func (c *fakeClock) wait(t *testing.T) chan time.Time {
+	t.Helper()
+	select {
+	case ch := <-c.waiting:
+		return ch
+	case <-time.After(time.Second):
+		require.FailNow(t, "timeout waiting for poll")
+		return nil
+	}
+}
+
+// advance advances c by d and wakes the watcher.
+func (c *fakeClock) advance(ch chan time.Time, d time.Duration) {
+	c.mutex.Lock()
+	c.now = c.now.Add(d)
+	now := c.now
+	c.mutex.Unlock()
+	ch <- now
+}
+
+type watcherEvent struct {
+	kind     string
+	location darksky.Location
+	title    string
+}
+
+func TestWatcher(t *testing.T) {
+	t0 := time.Unix(1556668800, 0).UTC()
+	santaMonica := darksky.Location{Latitude: 34.0219, Longitude: -118.4814}
+	nullIsland := darksky.Location{Latitude: 0, Longitude: 0}
+	request := dstest.Request{
+		Latitude:  santaMonica.Latitude,
+		Longitude: santaMonica.Longitude,
+		Exclude:   "currently,daily,flags,hourly,minutely",
+		Lang:      darksky.LangEN,
+		Units:     darksky.UnitsUS,
+	}
+
+	heat := &darksky.Alert{
+		Title:    "Excessive Heat Warning",
+		Severity: darksky.SeverityWarning,
+		Time:     &darksky.Time{Time: t0},
+		Expires:  &darksky.Time{Time: t0.Add(6 * time.Hour)},
+		URI:      "https://alerts.weather.gov/1",
+	}
+	heatUpdated := *heat
+	heatUpdated.Expires = &darksky.Time{Time: t0.Add(12 * time.Hour)}
+	wind := &darksky.Alert{
+		Title:    "Wind Advisory",
+		Severity: darksky.SeverityAdvisory,
+		Time:     &darksky.Time{Time: t0},
+		Expires:  &darksky.Time{Time: t0.Add(time.Hour)},
+		URI:      "https://alerts.weather.gov/2",
+	}
+	forecastStr := func(alerts ...*darksky.Alert) string {
+		data, err := json.Marshal(&darksky.Forecast{
+			Latitude:  santaMonica.Latitude,
+			Longitude: santaMonica.Longitude,
+			Timezone:  "America/Los_Angeles",
+			Alerts:    alerts,
+		})
+		require.NoError(t, err)
+		return string(data)
+	}
+
+	s := dstest.NewServer(
+		dstest.WithForecast(request, forecastStr(heat)),
+	)
+	defer s.Close()
+	c, err := s.NewClient()
+	require.NoError(t, err)
+
+	var events []watcherEvent
+	var errorLocations []darksky.Location
+	record := func(kind string) func(darksky.Location, *darksky.Alert) {
+		return func(location darksky.Location, a *darksky.Alert) {
+			events = append(events, watcherEvent{kind: kind, location: location, title: a.Title})
+		}
+	}
+	clock := newFakeClock(t0)
+	w := &darksky.Watcher{
+		Client:    c,
+		Locations: []darksky.Location{santaMonica, nullIsland},
+		Interval:  time.Minute,
+		Clock:     clock,
+		OnNew:     record("new"),
+		OnUpdated: record("updated"),
+		OnExpired: record("expired"),
+		OnError: func(location darksky.Location, err error) {
+			assert.True(t, errors.Is(err, darksky.ErrNotFound))
+			errorLocations = append(errorLocations, location)
+		},
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	done := make(chan error)
+	go func() {
+		done <- w.Run(ctx)
+	}()
+
+	// The first poll finds the heat warning.
+	ch := clock.wait(t)
+	assert.Equal(t, []watcherEvent{
+		{kind: "new", location: santaMonica, title: "Excessive Heat Warning"},
+	}, events)
+	assert.Equal(t, []darksky.Location{nullIsland}, errorLocations)
+	assert.Equal(t, []string{"Excessive Heat Warning"}, alertTitles(w.Alerts(santaMonica)))
+	assert.Empty(t, w.Alerts(nullIsland))
+
+	// The heat warning is extended and a wind advisory is issued.
+	events = nil
+	s.SetForecast(request, forecastStr(&heatUpdated, wind, wind))
+	clock.advance(ch, time.Minute)
+	ch = clock.wait(t)
+	assert.Equal(t, []watcherEvent{
+		{kind: "new", location: santaMonica, title: "Wind Advisory"},
+		{kind: "updated", location: santaMonica, title: "Excessive Heat Warning"},
+	}, events)
+
+	// Nothing changes.
+	events = nil
+	clock.advance(ch, time.Minute)
+	ch = clock.wait(t)
+	assert.Empty(t, events)
+
+	// The wind advisory expires while still present in the response.
+	clock.advance(ch, time.Hour)
+	ch = clock.wait(t)
+	assert.Equal(t, []watcherEvent{
+		{kind: "expired", location: santaMonica, title: "Wind Advisory"},
+	}, events)
+
+	// The heat warning is removed from the response.
+	events = nil
+	s.SetForecast(request, forecastStr())
+	clock.advance(ch, time.Minute)
+	clock.wait(t)
+	assert.Equal(t, []watcherEvent{
+		{kind: "expired", location: santaMonica, title: "Excessive Heat Warning"},
+	}, events)
+	assert.Empty(t, w.Alerts(santaMonica))
+
+	cancel()
+	select {
+	case err := <-done:
+		assert.Equal(t, context.Canceled, err)
+	case <-time.After(time.Second):
+		require.FailNow(t, "timeout waiting for Run to return")
+	}
+}
+
+func alertTitles(alerts []*darksky.Alert) []string {
+	var titles []string
+	for _, a := range alerts {
+		titles = append(titles, a.Title)
+	}
+	return titles
+}